@@ -2,9 +2,12 @@ package addrconv
 
 import (
 	"encoding/hex"
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/coinhako/addrconv/address"
+	"github.com/coinhako/addrconv/cashaddr"
 	"github.com/coinhako/blockutils"
 )
 
@@ -104,8 +107,8 @@ func TestBTCP2PK(t *testing.T) {
 	var script blockutils.Script
 	var err error
 
-	var scripts = []string{"4104f601d3111e0f502f8d5927fd4077e8723f9b156138a776afa5ceae4d6da7370d4f1effb4171cd8ce7b40d54e3a0b45b528575ce63465986810085babdef06f01ac", "2103f601d3111e0f502f8d5927fd4077e8723f9b156138a776afa5ceae4d6da7370dac", "410411db93e1dcdb8a016b49840f8c53bc1eb68a382e97b1482ecad7b148a6909a5cb2e0eaddfb84ccf9744464f82e160bfa9b8b64f9d4c03f999b8643f656b412a3ac"}
-	var addresses = []string{"18AuG6THx5V9JuZbtWtrabYWfxY28YcXhN", "1ABTTFaiYdTNX4yzQ1vn5QP1URrHwCDbFc", "12cbQLTFMXRnSzktFkuoG3eHoMeFtpTu3S"}
+	var scripts = []string{"4104f601d3111e0f502f8d5927fd4077e8723f9b156138a776afa5ceae4d6da7370d4f1effb4171cd8ce7b40d54e3a0b45b528575ce63465986810085babdef06f01ac", "2103f601d3111e0f502f8d5927fd4077e8723f9b156138a776afa5ceae4d6da7370dac", "410411db93e1dcdb8a016b49840f8c53bc1eb68a382e97b1482ecad7b148a6909a5cb2e0eaddfb84ccf9744464f82e160bfa9b8b64f9d4c03f999b8643f656b412a3ac", "410679be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8ac"}
+	var addresses = []string{"18AuG6THx5V9JuZbtWtrabYWfxY28YcXhN", "1ABTTFaiYdTNX4yzQ1vn5QP1URrHwCDbFc", "12cbQLTFMXRnSzktFkuoG3eHoMeFtpTu3S", "1H7NX5uHwz2Ks5JSqeDcUpvRPNubMhLoLN"}
 
 	for i, v := range scripts {
 
@@ -189,3 +192,172 @@ func TestDecodeCashAddr(t *testing.T) {
 		}
 	}
 }
+
+func TestDecodeBech32(t *testing.T) {
+	var scripts = []string{"751e76e8199196d454941c45d1b3a323f1433bd6", "701a8d401c84fb13e6baf169d59684e17abd9fa216c8cc5b9fc63d622ff8c58d", "751e76e8199196d454941c45d1b3a323f1433bd6"}
+	var addresses = []string{"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", "bc1qwqdg6squsna38e46795at95yu9atm8azzmyvckulcc7kytlcckxswvvzej", "BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4"}
+	var versions = []address.AddressType{address.P2WPKH, address.P2WSH, address.P2WPKH}
+
+	for i, v := range addresses {
+		decodedAddress, err := FromAddress(v)
+		if err != nil {
+			t.Errorf("Error decoding address: %s", err)
+		}
+		script := blockutils.Script(decodedAddress.Hash)
+		if script.String() != scripts[i] {
+			t.Errorf("Incorrect address. Expected %s, got %s", scripts[i], script)
+		}
+
+		if decodedAddress.Type != versions[i] {
+			t.Errorf("Incorrect address version. Expected %#x, got %#x", versions[i], decodedAddress.Type)
+		}
+
+		if decodedAddress.Bech32HRP != "bc" {
+			t.Errorf("Incorrect bech32 hrp. Expected %s, got %s", "bc", decodedAddress.Bech32HRP)
+		}
+	}
+
+	_, err := FromNetworkAddress("ltc1qw508d6qejxtdg4y5r3zarvary0c5xw7kgmn4n9", BitcoinNetwork)
+	if err == nil {
+		t.Errorf("Expected error decoding litecoin address on bitcoin network")
+	}
+}
+
+func TestTaprootAddresses(t *testing.T) {
+	var scripts = []string{"512079be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798", "5120a60869f0dbcf1dc659c9cecbaf8050135ea9e8cdc487053f1dc6880949dc684c"}
+	var addresses = []string{"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0", "bc1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxqkedrcr"}
+
+	for i, v := range scripts {
+		script, err := hex.DecodeString(v)
+		if err != nil {
+			t.Errorf("Error decoding hex: %s", err)
+		}
+		encodedAddress, err := ToNetworkAddress(script, BitcoinNetwork)
+		if err != nil {
+			t.Errorf("Error encoding address: %s", err)
+		}
+
+		if encodedAddress != addresses[i] {
+			t.Errorf("Incorrect address. Expected %s, got %s", addresses[i], encodedAddress)
+		}
+
+		decodedAddress, err := FromAddress(addresses[i])
+		if err != nil {
+			t.Errorf("Error decoding address: %s", err)
+		}
+
+		if decodedAddress.Type != address.P2TR {
+			t.Errorf("Incorrect address version. Expected %#x, got %#x", address.P2TR, decodedAddress.Type)
+		}
+
+		if blockutils.Script(decodedAddress.Hash).String() != v[4:] {
+			t.Errorf("Incorrect witness program. Expected %s, got %x", v[4:], decodedAddress.Hash)
+		}
+	}
+}
+
+func TestDecodeNetworkBase58(t *testing.T) {
+	var networks = []Network{DigibyteNetwork, BitcoinNetwork, BitcoinNetwork}
+	var addresses = []string{"DCXiSSQwi7gw9YXrMY4mxt2i4hQZEBb5Yv", "1JJ2o6iKB4UXVMHXBSzVvbAKim5su2VUfa", "38XEixUj1QpcqxTWbxvqdbv4Mjre4imw9Z"}
+	var scripts = []string{"510fffca0668d410aea742e95a2fefa7952f695e", "bdb2b538e6b07e93d6bafcef4bec9dc936818a19", "4aef67ed61d391d6f3d9903ead92386c1efc9925"}
+	var versions = []byte{0x1e, 0x00, 0x05}
+	var types = []address.AddressType{address.P2PKH, address.P2PKH, address.P2SH}
+
+	for i, v := range addresses {
+		decodedAddress, err := FromNetworkAddress(v, networks[i])
+		if err != nil {
+			t.Errorf("Error decoding address: %s", err)
+		}
+
+		if blockutils.Script(decodedAddress.Hash).String() != scripts[i] {
+			t.Errorf("Incorrect address. Expected %s, got %x", scripts[i], decodedAddress.Hash)
+		}
+
+		if decodedAddress.Version != versions[i] {
+			t.Errorf("Incorrect version byte. Expected %#x, got %#x", versions[i], decodedAddress.Version)
+		}
+
+		if decodedAddress.Type != types[i] {
+			t.Errorf("Incorrect address version. Expected %#x, got %#x", types[i], decodedAddress.Type)
+		}
+	}
+
+	// Every network should classify its own prefixes
+	networks = []Network{BitcoinNetwork, LitecoinNetwork, DogecoinNetwork, DigibyteNetwork, ZcoinNetwork}
+	for _, network := range networks {
+		for _, addressType := range []address.AddressType{address.P2PKH, address.P2SH} {
+			var original address.Address
+			original.Hash, _ = hex.DecodeString(scripts[1])
+			original.Type = addressType
+
+			encodedAddress, err := network.EncodeToBase58(original)
+			if err != nil {
+				t.Errorf("Error encoding address: %s", err)
+			}
+
+			decodedAddress, err := network.Decode(encodedAddress)
+			if err != nil {
+				t.Errorf("Error decoding address %s: %s", encodedAddress, err)
+			}
+
+			if decodedAddress.Type != addressType {
+				t.Errorf("Incorrect address version for %s. Expected %#x, got %#x", encodedAddress, addressType, decodedAddress.Type)
+			}
+		}
+	}
+
+	// Addresses from another network must be rejected
+	_, err := FromNetworkAddress("1JJ2o6iKB4UXVMHXBSzVvbAKim5su2VUfa", LitecoinNetwork)
+	if err == nil {
+		t.Errorf("Expected error decoding bitcoin address on litecoin network")
+	}
+
+	_, err = FromNetworkAddress("DCXiSSQwi7gw9YXrMY4mxt2i4hQZEBb5Yv", BitcoinNetwork)
+	if err == nil {
+		t.Errorf("Expected error decoding digibyte address on bitcoin network")
+	}
+}
+
+func TestCashAddrPrefixes(t *testing.T) {
+	hash, _ := hex.DecodeString("fc916f213a3d7f1369313d5fa30f6168f9446a2d")
+	var networks = []Network{BitcoinCashNetwork, BitcoinCashNetwork, BitcoinCashTestnetNetwork, BitcoinCashTestnetNetwork, BitcoinCashRegtestNetwork, ECashNetwork, ECashTestnetNetwork}
+	var prefixes = []string{"bitcoincash", "simpleledger", "bchtest", "slptest", "bchreg", "ecash", "ectest"}
+
+	for i, network := range networks {
+		encodedAddress, err := cashaddr.CheckEncodeCashAddress(hash, prefixes[i], address.P2PKH)
+		if err != nil {
+			t.Errorf("Error encoding address: %s", err)
+			continue
+		}
+
+		// With the prefix, and without it, uppercase too
+		payload := encodedAddress[len(prefixes[i])+1:]
+		for _, v := range []string{encodedAddress, payload, strings.ToUpper(payload)} {
+			decodedAddress, err := network.Decode(v)
+			if err != nil {
+				t.Errorf("Error decoding %s on %s: %s", v, network.Name, err)
+				continue
+			}
+
+			if strings.ToLower(decodedAddress.CashAddrPrefix) != prefixes[i] || decodedAddress.Type != address.P2PKH {
+				t.Errorf("Incorrect prefix for %s. Expected %s, got %s", v, prefixes[i], decodedAddress.CashAddrPrefix)
+			}
+		}
+
+		// Every other network must refuse the explicit prefix
+		for _, other := range []Network{BitcoinCashNetwork, BitcoinCashTestnetNetwork, BitcoinCashRegtestNetwork, ECashNetwork, ECashTestnetNetwork} {
+			if other.Name == network.Name {
+				continue
+			}
+
+			_, err := other.Decode(encodedAddress)
+			if !errors.Is(err, address.ErrInvalidPrefix) {
+				t.Errorf("Expected ErrInvalidPrefix decoding %s on %s, got %v", encodedAddress, other.Name, err)
+			}
+
+			if _, err := other.Decode(payload); err == nil {
+				t.Errorf("Expected error decoding %s on %s", payload, other.Name)
+			}
+		}
+	}
+}
@@ -11,13 +11,29 @@ const (
 	P2WPKH      AddressType = 5
 	P2WSH       AddressType = 6
 	P2PK        AddressType = 7
+	P2TR        AddressType = 8
+	P2SH32      AddressType = 9  // BCH OP_HASH256 script hash, never a classic P2SH
+	P2MS        AddressType = 10 // bare OP_CHECKMULTISIG output
+
+	// Outputs without an address, only reported by script classification
+	NULL_DATA       AddressType = 11 // OP_RETURN output
+	NONSTANDARD     AddressType = 12
+	WITNESS_UNKNOWN AddressType = 13 // segwit output with a future witness version
 )
 
 type Address struct {
 	Type           AddressType
 	Hash           []byte
+	Version        byte // base58 version byte, required for base58 addresses, see ToScript
 	Bech32HRP      string
 	CashAddrPrefix string
+	TokenAware     bool // cashaddr CashTokens token-aware type, can receive tokens
+	LegacyPrefix   bool // base58 version byte is one the network has deprecated
+
+	// Raw public keys of outputs that pay to keys rather than hashes,
+	// only set for P2PK and P2MS
+	PubKeys   [][]byte
+	Threshold int // signatures required to spend a P2MS output
 }
 
 func (address Address) IsP2SH() bool {
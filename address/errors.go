@@ -0,0 +1,95 @@
+package address
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Encoding is the string format an address is written in
+type Encoding int
+
+const (
+	NoEncoding Encoding = 0
+	Base58     Encoding = 1
+	Bech32     Encoding = 2
+	Bech32m    Encoding = 3
+	CashAddr   Encoding = 4
+
+	// Bech32 or bech32m, for errors found before the checksum tells
+	// them apart
+	Segwit Encoding = 5
+)
+
+func (encoding Encoding) String() string {
+	switch encoding {
+	case Base58:
+		return "base58"
+	case Bech32:
+		return "bech32"
+	case Bech32m:
+		return "bech32m"
+	case CashAddr:
+		return "cashaddr"
+	case Segwit:
+		return "bech32/bech32m"
+	}
+	return "unknown encoding"
+}
+
+// Reasons an address can fail to decode or encode. These are wrapped in
+// an *Error, so compare them with errors.Is.
+var (
+	ErrUnknownAddressType  = errors.New("unknown address type")
+	ErrInvalidFormat       = errors.New("invalid format")
+	ErrInvalidLength       = errors.New("invalid length")
+	ErrInvalidCharacter    = errors.New("invalid character")
+	ErrMixedCase           = errors.New("mixed case")
+	ErrInvalidChecksum     = errors.New("invalid checksum")
+	ErrInvalidPrefix       = errors.New("invalid prefix")
+	ErrInvalidVersion      = errors.New("invalid version")
+	ErrInvalidPadding      = errors.New("invalid padding")
+	ErrInvalidData         = errors.New("invalid data")
+	ErrInvalidScript       = errors.New("invalid script")
+	ErrUnsupportedType     = errors.New("address type not supported by encoding")
+	ErrUnsupportedEncoding = errors.New("encoding not supported by network")
+)
+
+// Error describes why an address failed to decode or encode
+type Error struct {
+	Encoding Encoding // the encoding that was tried
+	Err      error    // one of the Err* reasons above
+	Position int      // index of the offending character, -1 if not applicable
+
+	// Indices of the characters that were most likely mistyped, when a
+	// checksum failure could be located. These are only hints to show
+	// the user, nothing is ever corrected automatically.
+	ErrorPositions []int
+}
+
+// NewError returns an error that isn't tied to a character position
+func NewError(encoding Encoding, reason error) *Error {
+	return &Error{Encoding: encoding, Err: reason, Position: -1}
+}
+
+// NewPositionError returns an error for the character at position
+func NewPositionError(encoding Encoding, reason error, position int) *Error {
+	return &Error{Encoding: encoding, Err: reason, Position: position}
+}
+
+func (err *Error) Error() string {
+	msg := err.Err.Error()
+	if err.Encoding != NoEncoding {
+		msg = err.Encoding.String() + ": " + msg
+	}
+	if err.Position >= 0 {
+		msg += fmt.Sprintf(" at position %d", err.Position)
+	}
+	if len(err.ErrorPositions) > 0 {
+		msg += fmt.Sprintf(" (likely error at positions %v)", err.ErrorPositions)
+	}
+	return msg
+}
+
+func (err *Error) Unwrap() error {
+	return err.Err
+}
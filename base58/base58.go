@@ -1,7 +1,6 @@
 package base58
 
 import (
-	"errors"
 	"math/big"
 
 	"github.com/coinhako/addrconv/address"
@@ -82,7 +81,7 @@ func CheckEncode(input []byte, version byte) string {
 	return encode(b)
 }
 
-func decode(b string) []byte {
+func decode(b string) ([]byte, error) {
 	answer := big.NewInt(0)
 	j := big.NewInt(1)
 
@@ -90,7 +89,7 @@ func decode(b string) []byte {
 	for i := len(b) - 1; i >= 0; i-- {
 		tmp := b58[b[i]]
 		if tmp == 255 {
-			return []byte("")
+			return nil, address.NewPositionError(address.Base58, address.ErrInvalidCharacter, i)
 		}
 		scratch.SetInt64(int64(tmp))
 		scratch.Mul(j, scratch)
@@ -110,25 +109,31 @@ func decode(b string) []byte {
 	val := make([]byte, flen)
 	copy(val[numZeros:], tmpval)
 
-	return val
+	return val, nil
 }
 
 // CheckDecode decodes a string that was encoded with CheckEncode and verifies the checksum.
+// The type is guessed from the bitcoin version bytes, the raw version byte is
+// kept on the address so callers can classify it for other networks.
 func CheckDecode(input string) (decodedAddress address.Address, err error) {
-	decoded := decode(input)
+	decoded, err := decode(input)
+	if err != nil {
+		return decodedAddress, err
+	}
 	if len(decoded) < 5 {
-		return decodedAddress, errors.New("Invalid format")
+		return decodedAddress, address.NewError(address.Base58, address.ErrInvalidLength)
 	}
 	version := decoded[0]
 	var cksum [4]byte
 	copy(cksum[:], decoded[len(decoded)-4:])
 	if checksum(decoded[:len(decoded)-4]) != cksum {
-		return decodedAddress, errors.New("Invalid checksum")
+		return decodedAddress, address.NewError(address.Base58, address.ErrInvalidChecksum)
 	}
 	payload := decoded[1 : len(decoded)-4]
 	// result := append(result, payload...)
 
 	decodedAddress.Hash = payload
+	decodedAddress.Version = version
 	switch version {
 	case 0x00:
 		decodedAddress.Type = address.P2PKH
@@ -0,0 +1,88 @@
+package base58
+
+import (
+	"strings"
+
+	"github.com/coinhako/addrconv/address"
+)
+
+// Placeholder stands in for a single character that couldn't be read,
+// e.g. from a blurred screenshot
+const Placeholder = '?'
+
+// Recover returns every string that passes CheckDecode with one of the
+// given version bytes and is a single edit away from input: one
+// substituted character, two swapped neighbouring characters, or one
+// inserted or deleted character. If input contains a Placeholder only
+// that character is filled in. The candidates are for the user to pick
+// from, none of them should be used without confirmation.
+func Recover(input string, versions ...byte) ([]string, error) {
+	placeholders := strings.Count(input, string(Placeholder))
+	if placeholders > 1 {
+		return nil, address.NewPositionError(address.Base58, address.ErrInvalidCharacter, strings.LastIndex(input, string(Placeholder)))
+	}
+
+	if placeholders == 0 {
+		if _, err := CheckDecode(input); err == nil {
+			return nil, nil
+		}
+	}
+
+	var candidates []string
+	seen := make(map[string]bool)
+	try := func(candidate string) {
+		if seen[candidate] {
+			return
+		}
+		seen[candidate] = true
+
+		decodedAddress, err := CheckDecode(candidate)
+		if err != nil {
+			return
+		}
+		for _, version := range versions {
+			if decodedAddress.Version == version {
+				candidates = append(candidates, candidate)
+				return
+			}
+		}
+	}
+
+	if placeholders == 1 {
+		i := strings.IndexByte(input, Placeholder)
+		for _, c := range alphabet {
+			try(input[:i] + string(c) + input[i+1:])
+		}
+		return candidates, nil
+	}
+
+	// Substitutions
+	for i := 0; i < len(input); i++ {
+		for _, c := range alphabet {
+			if byte(c) != input[i] {
+				try(input[:i] + string(c) + input[i+1:])
+			}
+		}
+	}
+
+	// Transpositions
+	for i := 0; i+1 < len(input); i++ {
+		if input[i] != input[i+1] {
+			try(input[:i] + string(input[i+1]) + string(input[i]) + input[i+2:])
+		}
+	}
+
+	// Insertions
+	for i := 0; i <= len(input); i++ {
+		for _, c := range alphabet {
+			try(input[:i] + string(c) + input[i:])
+		}
+	}
+
+	// Deletions
+	for i := 0; i < len(input); i++ {
+		try(input[:i] + input[i+1:])
+	}
+
+	return candidates, nil
+}
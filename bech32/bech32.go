@@ -24,12 +24,40 @@ import (
 	"bytes"
 	"fmt"
 	"strings"
+
+	"github.com/coinhako/addrconv/address"
 )
 
 var charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
 
 var generator = []int{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
 
+// Encoding is the checksum variant of a bech32 string
+type Encoding int
+
+const (
+	// Bech32 is the original checksum from BIP173
+	Bech32 Encoding = 1
+	// Bech32m is the modified checksum from BIP350, used for witness version 1+
+	Bech32m Encoding = 2
+)
+
+const bech32mConst = 0x2bc830a3
+
+func (encoding Encoding) addressEncoding() address.Encoding {
+	if encoding == Bech32m {
+		return address.Bech32m
+	}
+	return address.Bech32
+}
+
+func (encoding Encoding) checksumConst() int {
+	if encoding == Bech32m {
+		return bech32mConst
+	}
+	return 1
+}
+
 func polymod(values []int) int {
 	chk := 1
 	for _, v := range values {
@@ -56,13 +84,19 @@ func hrpExpand(hrp string) []int {
 	return ret
 }
 
-func verifyChecksum(hrp string, data []int) bool {
-	return polymod(append(hrpExpand(hrp), data...)) == 1
+func verifyChecksum(hrp string, data []int) (Encoding, bool) {
+	switch polymod(append(hrpExpand(hrp), data...)) {
+	case Bech32.checksumConst():
+		return Bech32, true
+	case Bech32m.checksumConst():
+		return Bech32m, true
+	}
+	return 0, false
 }
 
-func createChecksum(hrp string, data []int) []int {
+func createChecksum(hrp string, data []int, encoding Encoding) []int {
 	values := append(append(hrpExpand(hrp), data...), []int{0, 0, 0, 0, 0, 0}...)
-	mod := polymod(values) ^ 1
+	mod := polymod(values) ^ encoding.checksumConst()
 	ret := make([]int, 6)
 	for p := 0; p < len(ret); p++ {
 		ret[p] = (mod >> uint(5*(5-p))) & 31
@@ -73,29 +107,38 @@ func createChecksum(hrp string, data []int) []int {
 // Encode encodes hrp(human-readable part) and data(32bit data array), returns Bech32 / or error
 // if hrp is uppercase, return uppercase Bech32
 func Encode(hrp string, data []int) (string, error) {
+	return encode(hrp, data, Bech32)
+}
+
+// EncodeM is like Encode, but uses the Bech32m checksum from BIP350
+func EncodeM(hrp string, data []int) (string, error) {
+	return encode(hrp, data, Bech32m)
+}
+
+func encode(hrp string, data []int, encoding Encoding) (string, error) {
 	if (len(hrp) + len(data) + 7) > 90 {
-		return "", fmt.Errorf("too long : hrp length=%d, data length=%d", len(hrp), len(data))
+		return "", address.NewError(encoding.addressEncoding(), address.ErrInvalidLength)
 	}
 	if len(hrp) < 1 {
-		return "", fmt.Errorf("invalid hrp : hrp=%v", hrp)
+		return "", address.NewError(encoding.addressEncoding(), address.ErrInvalidPrefix)
 	}
 	for p, c := range hrp {
 		if c < 33 || c > 126 {
-			return "", fmt.Errorf("invalid character human-readable part : hrp[%d]=%d", p, c)
+			return "", address.NewPositionError(encoding.addressEncoding(), address.ErrInvalidPrefix, p)
 		}
 	}
 	if strings.ToUpper(hrp) != hrp && strings.ToLower(hrp) != hrp {
-		return "", fmt.Errorf("mix case : hrp=%v", hrp)
+		return "", address.NewError(encoding.addressEncoding(), address.ErrMixedCase)
 	}
 	lower := strings.ToLower(hrp) == hrp
 	hrp = strings.ToLower(hrp)
-	combined := append(data, createChecksum(hrp, data)...)
+	combined := append(data, createChecksum(hrp, data, encoding)...)
 	var ret bytes.Buffer
 	ret.WriteString(hrp)
 	ret.WriteString("1")
-	for idx, p := range combined {
+	for _, p := range combined {
 		if p < 0 || p >= len(charset) {
-			return "", fmt.Errorf("invalid data : data[%d]=%d", idx, p)
+			return "", address.NewError(encoding.addressEncoding(), address.ErrInvalidData)
 		}
 		ret.WriteByte(charset[p])
 	}
@@ -107,45 +150,63 @@ func Encode(hrp string, data []int) (string, error) {
 
 // Decode decodes bechString(Bech32) returns hrp(human-readable part) and data(32bit data array) / or error
 func Decode(bechString string) (string, []int, error) {
+	hrp, data, encoding, err := DecodeGeneric(bechString)
+	if err != nil {
+		return "", nil, err
+	}
+	if encoding != Bech32 {
+		return "", nil, address.NewError(address.Bech32, address.ErrInvalidChecksum)
+	}
+	return hrp, data, nil
+}
+
+// DecodeGeneric decodes bechString with either a Bech32 or Bech32m checksum, returns hrp(human-readable part),
+// data(32bit data array) and the checksum encoding that was found / or error
+func DecodeGeneric(bechString string) (string, []int, Encoding, error) {
 	if len(bechString) > 90 {
-		return "", nil, fmt.Errorf("too long : len=%d", len(bechString))
+		return "", nil, 0, address.NewError(address.Segwit, address.ErrInvalidLength)
 	}
 	if strings.ToLower(bechString) != bechString && strings.ToUpper(bechString) != bechString {
-		return "", nil, fmt.Errorf("mixed case")
+		return "", nil, 0, address.NewError(address.Segwit, address.ErrMixedCase)
 	}
 	bechString = strings.ToLower(bechString)
 	pos := strings.LastIndex(bechString, "1")
 	if pos < 1 || pos+7 > len(bechString) {
-		return "", nil, fmt.Errorf("separator '1' at invalid position : pos=%d , len=%d", pos, len(bechString))
+		return "", nil, 0, address.NewPositionError(address.Segwit, address.ErrInvalidFormat, pos)
 	}
 	hrp := bechString[0:pos]
 	for p, c := range hrp {
 		if c < 33 || c > 126 {
-			return "", nil, fmt.Errorf("invalid character human-readable part : bechString[%d]=%d", p, c)
+			return "", nil, 0, address.NewPositionError(address.Segwit, address.ErrInvalidPrefix, p)
 		}
 	}
 	data := []int{}
 	for p := pos + 1; p < len(bechString); p++ {
 		d := strings.Index(charset, fmt.Sprintf("%c", bechString[p]))
 		if d == -1 {
-			return "", nil, fmt.Errorf("invalid character data part : bechString[%d]=%d", p, bechString[p])
+			return "", nil, 0, address.NewPositionError(address.Segwit, address.ErrInvalidCharacter, p)
 		}
 		data = append(data, d)
 	}
-	if !verifyChecksum(hrp, data) {
-		return "", nil, fmt.Errorf("invalid checksum")
+	encoding, ok := verifyChecksum(hrp, data)
+	if !ok {
+		// Locating the errors is expensive, callers that want the
+		// positions can ask LocateErrors
+		return "", nil, 0, address.NewError(address.Segwit, address.ErrInvalidChecksum)
 	}
-	return hrp, data[:len(data)-6], nil
+	return hrp, data[:len(data)-6], encoding, nil
 }
 
-func convertbits(data []int, frombits, tobits uint, pad bool) ([]int, error) {
+// convertbits regroups data from frombits to tobits per value, errors are
+// tagged with the checksum encoding of the address being converted
+func convertbits(data []int, frombits, tobits uint, pad bool, encoding Encoding) ([]int, error) {
 	acc := 0
 	bits := uint(0)
 	ret := []int{}
 	maxv := (1 << tobits) - 1
-	for idx, value := range data {
+	for _, value := range data {
 		if value < 0 || (value>>frombits) != 0 {
-			return nil, fmt.Errorf("invalid data range : data[%d]=%d (frombits=%d)", idx, value, frombits)
+			return nil, address.NewError(encoding.addressEncoding(), address.ErrInvalidData)
 		}
 		acc = (acc << frombits) | value
 		bits += frombits
@@ -159,59 +220,92 @@ func convertbits(data []int, frombits, tobits uint, pad bool) ([]int, error) {
 			ret = append(ret, (acc<<(tobits-bits))&maxv)
 		}
 	} else if bits >= frombits {
-		return nil, fmt.Errorf("illegal zero padding")
+		return nil, address.NewError(encoding.addressEncoding(), address.ErrInvalidPadding)
 	} else if ((acc << (tobits - bits)) & maxv) != 0 {
-		return nil, fmt.Errorf("non-zero padding")
+		return nil, address.NewError(encoding.addressEncoding(), address.ErrInvalidPadding)
 	}
 	return ret, nil
 }
 
 // SegwitAddrDecode decodes hrp(human-readable part) Segwit Address(string), returns version(int) and data(bytes array) / or error
+// Witness version 0 must use the Bech32 checksum and version 1+ the Bech32m checksum (per BIP350)
 func SegwitAddrDecode(hrp, addr string) (int, []int, error) {
-	dechrp, data, err := Decode(addr)
+	dechrp, data, encoding, err := DecodeGeneric(addr)
 	if err != nil {
+		// The witness version tells which checksum the address should
+		// have had, when the checksum itself couldn't
+		if addrErr, ok := err.(*address.Error); ok && addrErr.Encoding == address.Segwit {
+			if version, ok := witnessVersionChar(addr); ok {
+				addrErr.Encoding = segwitEncoding(version).addressEncoding()
+			}
+		}
 		return -1, nil, err
 	}
 	if dechrp != hrp {
-		return -1, nil, fmt.Errorf("invalid human-readable part : %s != %s", hrp, dechrp)
+		return -1, nil, address.NewError(encoding.addressEncoding(), address.ErrInvalidPrefix)
 	}
 	if len(data) < 1 {
-		return -1, nil, fmt.Errorf("invalid decode data length : %d", len(data))
+		return -1, nil, address.NewError(encoding.addressEncoding(), address.ErrInvalidLength)
 	}
 	if data[0] > 16 {
-		return -1, nil, fmt.Errorf("invalid witness version : %d", data[0])
+		return -1, nil, address.NewError(encoding.addressEncoding(), address.ErrInvalidVersion)
+	}
+	if encoding != segwitEncoding(data[0]) {
+		return -1, nil, address.NewError(encoding.addressEncoding(), address.ErrInvalidChecksum)
 	}
-	res, err := convertbits(data[1:], 5, 8, false)
+	res, err := convertbits(data[1:], 5, 8, false, encoding)
 	if err != nil {
 		return -1, nil, err
 	}
 	if len(res) < 2 || len(res) > 40 {
-		return -1, nil, fmt.Errorf("invalid convertbits length : %d", len(res))
+		return -1, nil, address.NewError(encoding.addressEncoding(), address.ErrInvalidLength)
 	}
 	if data[0] == 0 && len(res) != 20 && len(res) != 32 {
-		return -1, nil, fmt.Errorf("invalid program length for witness version 0 (per BIP141) : %d", len(res))
+		return -1, nil, address.NewError(encoding.addressEncoding(), address.ErrInvalidLength)
 	}
 	return data[0], res, nil
 }
 
 // SegwitAddrEncode encodes hrp(human-readable part) , version(int) and data(bytes array), returns Segwit Address / or error
+// Witness version 0 is encoded with the Bech32 checksum and version 1+ with the Bech32m checksum (per BIP350)
 func SegwitAddrEncode(hrp string, version int, program []int) (string, error) {
 	if version < 0 || version > 16 {
-		return "", fmt.Errorf("invalid witness version : %d", version)
+		return "", address.NewError(address.Segwit, address.ErrInvalidVersion)
 	}
 	if len(program) < 2 || len(program) > 40 {
-		return "", fmt.Errorf("invalid program length : %d", len(program))
+		return "", address.NewError(segwitEncoding(version).addressEncoding(), address.ErrInvalidLength)
 	}
 	if version == 0 && len(program) != 20 && len(program) != 32 {
-		return "", fmt.Errorf("invalid program length for witness version 0 (per BIP141) : %d", len(program))
+		return "", address.NewError(address.Bech32, address.ErrInvalidLength)
 	}
-	data, err := convertbits(program, 8, 5, true)
+	data, err := convertbits(program, 8, 5, true, segwitEncoding(version))
 	if err != nil {
 		return "", err
 	}
-	ret, err := Encode(hrp, append([]int{version}, data...))
+	ret, err := encode(hrp, append([]int{version}, data...), segwitEncoding(version))
 	if err != nil {
 		return "", err
 	}
 	return ret, nil
 }
+
+func segwitEncoding(version int) Encoding {
+	if version == 0 {
+		return Bech32
+	}
+	return Bech32m
+}
+
+// witnessVersionChar reads the witness version from the first character
+// after the separator, without decoding the rest of the address
+func witnessVersionChar(addr string) (int, bool) {
+	pos := strings.LastIndex(addr, "1")
+	if pos < 0 || pos+1 >= len(addr) {
+		return 0, false
+	}
+	version := strings.IndexByte(charset, strings.ToLower(addr)[pos+1])
+	if version < 0 || version > 16 {
+		return 0, false
+	}
+	return version, true
+}
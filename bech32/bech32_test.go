@@ -20,10 +20,12 @@
 package bech32_test
 
 import (
+	"errors"
 	"reflect"
 	"strings"
 	"testing"
 
+	"github.com/coinhako/addrconv/address"
 	"github.com/coinhako/addrconv/bech32"
 )
 
@@ -53,6 +55,16 @@ var invalidChecksum = []string{
 	"de1lg7wt\xFF",
 }
 
+var validChecksumM = []string{
+	"A1LQFN3A",
+	"a1lqfn3a",
+	"an83characterlonghumanreadablepartthatcontainsthetheexcludedcharactersbioandnumber11sg7hg6",
+	"abcdef1l7aum6echk45nj3s0wdvt2fg8x9yrzpqzd3ryx",
+	"11llllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllludsr8",
+	"split1checkupstagehandshakeupstreamerranterredcaperredlc445v",
+	"?1v759aa",
+}
+
 type item struct {
 	address      string
 	scriptpubkey []int
@@ -73,7 +85,7 @@ var validAddress = []item{
 			0x62,
 		},
 	},
-	item{"bc1pw508d6qejxtdg4y5r3zarvary0c5xw7kw508d6qejxtdg4y5r3zarvary0c5xw7k7grplx",
+	item{"bc1pw508d6qejxtdg4y5r3zarvary0c5xw7kw508d6qejxtdg4y5r3zarvary0c5xw7kt5nd6y",
 		[]int{
 			0x51, 0x28, 0x75, 0x1e, 0x76, 0xe8, 0x19, 0x91, 0x96, 0xd4, 0x54,
 			0x94, 0x1c, 0x45, 0xd1, 0xb3, 0xa3, 0x23, 0xf1, 0x43, 0x3b, 0xd6,
@@ -81,12 +93,12 @@ var validAddress = []item{
 			0x45, 0xd1, 0xb3, 0xa3, 0x23, 0xf1, 0x43, 0x3b, 0xd6,
 		},
 	},
-	item{"BC1SW50QA3JX3S",
+	item{"BC1SW50QGDZ25J",
 		[]int{
 			0x60, 0x02, 0x75, 0x1e,
 		},
 	},
-	item{"bc1zw508d6qejxtdg4y5r3zarvaryvg6kdaj",
+	item{"bc1zw508d6qejxtdg4y5r3zarvaryvaxxpcs",
 		[]int{
 			0x52, 0x10, 0x75, 0x1e, 0x76, 0xe8, 0x19, 0x91, 0x96, 0xd4, 0x54,
 			0x94, 0x1c, 0x45, 0xd1, 0xb3, 0xa3, 0x23,
@@ -100,6 +112,22 @@ var validAddress = []item{
 			0x33,
 		},
 	},
+	item{"tb1pqqqqp399et2xygdj5xreqhjjvcmzhxw4aywxecjdzew6hylgvsesf3hn0c",
+		[]int{
+			0x51, 0x20, 0x00, 0x00, 0x00, 0xc4, 0xa5, 0xca, 0xd4, 0x62, 0x21,
+			0xb2, 0xa1, 0x87, 0x90, 0x5e, 0x52, 0x66, 0x36, 0x2b, 0x99, 0xd5,
+			0xe9, 0x1c, 0x6c, 0xe2, 0x4d, 0x16, 0x5d, 0xab, 0x93, 0xe8, 0x64,
+			0x33,
+		},
+	},
+	item{"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0",
+		[]int{
+			0x51, 0x20, 0x79, 0xbe, 0x66, 0x7e, 0xf9, 0xdc, 0xbb, 0xac, 0x55,
+			0xa0, 0x62, 0x95, 0xce, 0x87, 0x0b, 0x07, 0x02, 0x9b, 0xfc, 0xdb,
+			0x2d, 0xce, 0x28, 0xd9, 0x59, 0xf2, 0x81, 0x5b, 0x16, 0xf8, 0x17,
+			0x98,
+		},
+	},
 }
 
 var invalidAddress = []string{
@@ -113,6 +141,20 @@ var invalidAddress = []string{
 	"bc1zw508d6qejxtdg4y5r3zarvaryvqyzf3du",
 	"tb1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3pjxtptv",
 	"bc1gmk9yu",
+	// BIP350
+	"tc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vq5zuyut",
+	"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqh2y7hd",
+	"tb1z0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqglt7rf",
+	"BC1S0XLXVLHEMJA6C4DQV22UAPCTQUPFHLXM9H8Z3K2E72Q4K9HCZ7VQ54WELL",
+	"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kemeawh",
+	"tb1q0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vq24jc47",
+	"bc1p38j9r5y49hruaue7wxjce0updqjuyyx0kh56v8s25huc6995vvpql3jow4",
+	"BC130XLXVLHEMJA6C4DQV22UAPCTQUPFHLXM9H8Z3K2E72Q4K9HCZ7VQ7ZWS8R",
+	"bc1pw5dgrnzv",
+	"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7v8n0nx0muaewav253zgeav",
+	"tb1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vq47Zagq",
+	"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7v07qwwzcrf",
+	"tb1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vpggkg4j",
 }
 
 func TestValidChecksum(t *testing.T) {
@@ -126,6 +168,20 @@ func TestValidChecksum(t *testing.T) {
 	}
 }
 
+func TestValidChecksumM(t *testing.T) {
+	for _, test := range validChecksumM {
+		hrp, data, encoding, err := bech32.DecodeGeneric(test)
+		if err != nil || encoding != bech32.Bech32m {
+			t.Errorf("Valid bech32m checksum for %s : FAIL / error %+v\n", test, err)
+		} else {
+			t.Logf("Valid bech32m checksum for %s : ok / hrp : %+v , data : %+v\n", test, hrp, data)
+		}
+		if _, _, err = bech32.Decode(test); err == nil {
+			t.Errorf("Bech32m checksum accepted by Decode for %s : FAIL\n", test)
+		}
+	}
+}
+
 func TestInvalidChecksum(t *testing.T) {
 	for _, test := range invalidChecksum {
 		hrp, data, err := bech32.Decode(test)
@@ -294,13 +350,13 @@ func TestCoverage(t *testing.T) {
 	} else {
 		t.Log("Coverage Decode separator '1' at invalid position error case : ok / error :", err)
 	}
-	_, _, err = bech32.Decode("a" + string(32) + "1qqqqqq")
+	_, _, err = bech32.Decode("a" + string(rune(32)) + "1qqqqqq")
 	if err == nil {
 		t.Errorf("Coverage Decode invalid character human-readable part error case : FAIL")
 	} else {
 		t.Log("Coverage Decode invalid character human-readable part error case : ok / error :", err)
 	}
-	_, _, err = bech32.Decode("a" + string(127) + "1qqqqqq")
+	_, _, err = bech32.Decode("a" + string(rune(127)) + "1qqqqqq")
 	if err == nil {
 		t.Errorf("Coverage Decode invalid character human-readable part error case : FAIL")
 	} else {
@@ -353,7 +409,7 @@ func TestCoverage(t *testing.T) {
 	} else {
 		t.Log("Coverage Encode mix case error case : ok / error : ", err)
 	}
-	hrp = string(33) + string(126)
+	hrp = string(rune(33)) + string(rune(126))
 	data = make([]int, 90-7-len(hrp))
 	bech32String, err = bech32.Encode(hrp, data)
 	if err != nil {
@@ -361,7 +417,7 @@ func TestCoverage(t *testing.T) {
 	} else {
 		t.Log("Coverage Encode normal case : ok / bech32String : ", bech32String)
 	}
-	hrp = string(32) + "c"
+	hrp = string(rune(32)) + "c"
 	data = make([]int, 90-7-len(hrp))
 	bech32String, err = bech32.Encode(hrp, data)
 	if err == nil {
@@ -369,7 +425,7 @@ func TestCoverage(t *testing.T) {
 	} else {
 		t.Log("Coverage Encode invalid character human-readable part error case : ok / error : ", err)
 	}
-	hrp = "b" + string(127)
+	hrp = "b" + string(rune(127))
 	data = make([]int, 90-7-len(hrp))
 	bech32String, err = bech32.Encode(hrp, data)
 	if err == nil {
@@ -402,3 +458,32 @@ func TestCoverage(t *testing.T) {
 		t.Log("Coverage Encode invalid data error case : ok / error : ", err)
 	}
 }
+
+func TestErrorEncodings(t *testing.T) {
+	var addresses = []string{
+		"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8fbt4",
+		"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jjb",
+	}
+	var encodings = []address.Encoding{address.Bech32, address.Bech32m}
+
+	for i, v := range addresses {
+		// The checksum can't tell which encoding was meant
+		_, _, _, err := bech32.DecodeGeneric(v)
+		var addrErr *address.Error
+		if !errors.As(err, &addrErr) || addrErr.Encoding != address.Segwit {
+			t.Errorf("Expected a segwit error for %s, got %v", v, err)
+		}
+
+		// The witness version can
+		_, _, err = bech32.SegwitAddrDecode("bc", v)
+		if !errors.As(err, &addrErr) || addrErr.Encoding != encodings[i] {
+			t.Errorf("Expected a %s error for %s, got %v", encodings[i], v, err)
+		}
+	}
+
+	_, err := bech32.SegwitAddrEncode("bc", 1, []int{-1, 0})
+	var addrErr *address.Error
+	if !errors.As(err, &addrErr) || addrErr.Encoding != address.Bech32m || !errors.Is(err, address.ErrInvalidData) {
+		t.Errorf("Expected a bech32m invalid data error, got %v", err)
+	}
+}
@@ -0,0 +1,119 @@
+package bech32
+
+import (
+	"strings"
+)
+
+// The BCH code behind the bech32 checksums can locate up to two
+// substituted characters in the data part. polymod is linear over
+// GF(2), so the difference between the checksum of the string and the
+// expected constant (the syndrome) is the sum of the contributions of
+// every error. Each position and error value has a fixed contribution,
+// so we look for the one or two contributions that add up to the
+// syndrome.
+
+// LocateErrors returns the indices of the characters in bechString that
+// were most likely mistyped, and the checksum encoding they were located
+// against. It returns nil when the string is valid, when the errors are
+// not in the data part, or when they can't be located unambiguously.
+// The positions are hints only, nothing is corrected.
+func LocateErrors(bechString string) ([]int, Encoding) {
+	if len(bechString) > 90 {
+		return nil, 0
+	}
+	if strings.ToLower(bechString) != bechString && strings.ToUpper(bechString) != bechString {
+		return nil, 0
+	}
+	bechString = strings.ToLower(bechString)
+	pos := strings.LastIndex(bechString, "1")
+	if pos < 1 || pos+7 > len(bechString) {
+		return nil, 0
+	}
+	hrp := bechString[0:pos]
+	data := []int{}
+	for p := pos + 1; p < len(bechString); p++ {
+		d := strings.IndexByte(charset, bechString[p])
+		if d == -1 {
+			return nil, 0
+		}
+		data = append(data, d)
+	}
+	if _, ok := verifyChecksum(hrp, data); ok {
+		return nil, 0
+	}
+
+	positions, encoding := locateErrors(hrp, data)
+	for i := range positions {
+		positions[i] += pos + 1
+	}
+	return positions, encoding
+}
+
+// locateErrors returns indices into data (checksum included)
+func locateErrors(hrp string, data []int) ([]int, Encoding) {
+	values := append(hrpExpand(hrp), data...)
+	offset := len(values) - len(data)
+	residue := polymod(values)
+
+	// contributions[p][e] is what xoring e into data[p] does to polymod
+	contributions := make([][32]int, len(data))
+	for p := range data {
+		for e := 1; e < 32; e++ {
+			values[offset+p] ^= e
+			contributions[p][e] = polymod(values) ^ residue
+			values[offset+p] ^= e
+		}
+	}
+
+	bech32Positions := locateSyndrome(contributions, residue^Bech32.checksumConst())
+	bech32mPositions := locateSyndrome(contributions, residue^Bech32m.checksumConst())
+
+	// Prefer whichever explains the string with fewer errors
+	if bech32mPositions != nil && (bech32Positions == nil || len(bech32mPositions) < len(bech32Positions)) {
+		return bech32mPositions, Bech32m
+	}
+	if bech32Positions != nil {
+		return bech32Positions, Bech32
+	}
+	return nil, 0
+}
+
+func locateSyndrome(contributions [][32]int, syndrome int) []int {
+	// A single error is always unique
+	for p := range contributions {
+		for e := 1; e < 32; e++ {
+			if contributions[p][e] == syndrome {
+				return []int{p}
+			}
+		}
+	}
+
+	type location struct {
+		position int
+		value    int
+	}
+	byContribution := make(map[int][]location)
+	for p := range contributions {
+		for e := 1; e < 32; e++ {
+			byContribution[contributions[p][e]] = append(byContribution[contributions[p][e]], location{p, e})
+		}
+	}
+
+	var found []int
+	for p := range contributions {
+		for e := 1; e < 32; e++ {
+			for _, other := range byContribution[syndrome^contributions[p][e]] {
+				if other.position <= p {
+					continue
+				}
+				if found != nil && (found[0] != p || found[1] != other.position) {
+					// More than one pair of positions fits
+					return nil
+				}
+				found = []int{p, other.position}
+			}
+		}
+	}
+
+	return found
+}
@@ -0,0 +1,39 @@
+package bech32_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/coinhako/addrconv/bech32"
+)
+
+type locateItem struct {
+	address   string
+	positions []int
+	encoding  bech32.Encoding
+}
+
+var locateErrors = []locateItem{
+	// bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4 with substitutions
+	locateItem{"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", nil, 0},
+	locateItem{"bc1qw508d6qejqtdg4y5r3zarvary0c5xw7kv8f3t4", []int{13}, bech32.Bech32},
+	locateItem{"bc1qw508d6qejqtdg4y5r3zarvary0c5xw7kv8f3t5", []int{13, 41}, bech32.Bech32},
+	locateItem{"BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T5", []int{41}, bech32.Bech32},
+	// bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0 with substitutions
+	locateItem{"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0", nil, 0},
+	locateItem{"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz8vqzk5jj0", []int{53}, bech32.Bech32m},
+	locateItem{"bc1p0xlxvlhemja7c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz8vqzk5jj0", []int{15, 53}, bech32.Bech32m},
+}
+
+func TestLocateErrors(t *testing.T) {
+	for _, test := range locateErrors {
+		positions, encoding := bech32.LocateErrors(test.address)
+		if !reflect.DeepEqual(positions, test.positions) {
+			t.Errorf("Locate errors for %s : FAIL / expected %v, got %v\n", test.address, test.positions, positions)
+			continue
+		}
+		if test.positions != nil && encoding != test.encoding {
+			t.Errorf("Locate errors for %s : FAIL / expected encoding %d, got %d\n", test.address, test.encoding, encoding)
+		}
+	}
+}
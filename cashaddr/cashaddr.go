@@ -1,9 +1,6 @@
 package cashaddr
 
 import (
-	"errors"
-	"fmt"
-
 	"github.com/coinhako/addrconv/address"
 	// "encoding/hex"
 	// "encoding/binary"
@@ -19,7 +16,8 @@ import (
 // The payload further consists of 3 parts
 // A Version Byte consisting of:
 //     MSB is always 0
-//     4 bits for address type (P2PKH/P2SH)
+//     4 bits for address type (P2PKH/P2SH, or their token-aware
+//     versions from CHIP-2022-02 CashTokens)
 //     3 (LS)bits for the size of the hash (see spec link for size table)
 // A hash (this is the actual address bit)
 // A 40 bit checksum
@@ -37,6 +35,9 @@ var CHARSET_REVERSED = [128]int8{
 	3, 16, 11, 28, 12, 14, 6, 4, 2, -1, -1, -1, -1, -1,
 }
 
+// Hash sizes in bytes, indexed by the 3 size bits of the version byte
+var hashSizes = [8]int{20, 24, 28, 32, 40, 48, 56, 64}
+
 /**
  * Concatenate two byte arrays.
  */
@@ -53,18 +54,34 @@ func makeLowerCase(c byte) byte {
 	return c | 0x20
 }
 
-func packAddressData(addrType address.AddressType, addrHash []byte) ([]byte, error) {
+// Type bits of the version byte
+const (
+	typeP2PKH           = 0
+	typeP2SH            = 1
+	typeTokenAwareP2PKH = 2
+	typeTokenAwareP2SH  = 3
+)
+
+func packAddressData(addrType address.AddressType, addrHash []byte, tokenAware bool) ([]byte, error) {
 	// Pack addr data with version byte.
-	if addrType != address.P2PKH && addrType != address.P2SH {
-		return []byte{}, errors.New("invalid addrtype")
+	if addrType != address.P2PKH && addrType != address.P2SH && addrType != address.P2SH32 {
+		return []byte{}, address.NewError(address.CashAddr, address.ErrUnsupportedType)
 	}
 
 	var versionByte uint
-	switch addrType {
-	case address.P2PKH:
-		versionByte = 0
-	case address.P2SH:
-		versionByte = 8
+	switch {
+	case addrType == address.P2PKH && tokenAware:
+		versionByte = typeTokenAwareP2PKH << 3
+	case addrType == address.P2PKH:
+		versionByte = typeP2PKH << 3
+	case tokenAware:
+		versionByte = typeTokenAwareP2SH << 3
+	default:
+		versionByte = typeP2SH << 3
+	}
+
+	if addrType == address.P2SH32 && len(addrHash) != 32 {
+		return []byte{}, address.NewError(address.CashAddr, address.ErrInvalidLength)
 	}
 
 	// hash can only be of 160 | 192 | 224 | 256 | 320 | 384 | 448 | 512 size
@@ -99,7 +116,7 @@ func packAddressData(addrType address.AddressType, addrHash []byte) ([]byte, err
 		encodedSize = 7
 		break
 	default:
-		return []byte{}, errors.New("invalid address size")
+		return []byte{}, address.NewError(address.CashAddr, address.ErrInvalidLength)
 	}
 
 	versionByte |= encodedSize // Just some OR
@@ -110,7 +127,7 @@ func packAddressData(addrType address.AddressType, addrHash []byte) ([]byte, err
 	data := append([]byte{byte(versionByte)}, addrHashUint...)
 	packedData, err := convertBits(data, 8, 5, true) // Convert 8-bit to 5-bit with 0-left padding
 	if err != nil {
-		return []byte{}, err
+		return []byte{}, address.NewError(address.CashAddr, err)
 	}
 	return packedData, nil
 }
@@ -162,13 +179,22 @@ func Encode(prefix string, payload []byte) string {
 	return ret
 }
 
-func CheckEncodeCashAddress(input []byte, prefix string, t address.AddressType) string {
-	k, err := packAddressData(t, input)
+func CheckEncodeCashAddress(input []byte, prefix string, t address.AddressType) (string, error) {
+	return checkEncodeCashAddress(input, prefix, t, false)
+}
+
+// CheckEncodeTokenAwareCashAddress encodes a CashTokens token-aware
+// address (z... for P2PKH, r... for P2SH)
+func CheckEncodeTokenAwareCashAddress(input []byte, prefix string, t address.AddressType) (string, error) {
+	return checkEncodeCashAddress(input, prefix, t, true)
+}
+
+func checkEncodeCashAddress(input []byte, prefix string, t address.AddressType, tokenAware bool) (string, error) {
+	k, err := packAddressData(t, input, tokenAware)
 	if err != nil {
-		fmt.Printf("%v", err)
-		return ""
+		return "", err
 	}
-	return Encode(prefix, k)
+	return Encode(prefix, k), nil
 }
 
 /**
@@ -193,25 +219,64 @@ func CheckDecodeCashAddress(input string) (decodedAddress address.Address, err e
 	}
 	data, err = convertBits(data, 5, 8, false)
 	if err != nil {
-		return decodedAddress, err
-	}
-	if len(data) != 21 {
-		return decodedAddress, errors.New("Incorrect data length")
+		return decodedAddress, address.NewError(address.CashAddr, err)
 	}
 
-	switch data[0] {
-	case 0x00:
-		decodedAddress.Type = address.P2PKH
-	case 0x08:
-		decodedAddress.Type = address.P2SH
+	addrType, addrHash, tokenAware, err := unpackAddressData(data)
+	if err != nil {
+		return decodedAddress, err
 	}
 
-	decodedAddress.Hash = data[1:21]
+	decodedAddress.Type = addrType
+	decodedAddress.Hash = addrHash
+	decodedAddress.TokenAware = tokenAware
 	decodedAddress.CashAddrPrefix = prefix
 
 	return decodedAddress, nil
 }
 
+// unpackAddressData is the reverse of packAddressData, after the payload
+// has been converted back to 8-bit bytes
+func unpackAddressData(data []byte) (addrType address.AddressType, addrHash []byte, tokenAware bool, err error) {
+	if len(data) < 1 {
+		return address.UNKNOWN, []byte{}, false, address.NewError(address.CashAddr, address.ErrInvalidLength)
+	}
+
+	versionByte := data[0]
+	// The MSB is reserved and must be 0
+	if versionByte&0x80 != 0 {
+		return address.UNKNOWN, []byte{}, false, address.NewError(address.CashAddr, address.ErrInvalidVersion)
+	}
+
+	switch versionByte >> 3 {
+	case typeP2PKH:
+		addrType = address.P2PKH
+	case typeP2SH:
+		addrType = address.P2SH
+	case typeTokenAwareP2PKH:
+		addrType = address.P2PKH
+		tokenAware = true
+	case typeTokenAwareP2SH:
+		addrType = address.P2SH
+		tokenAware = true
+	default:
+		return address.UNKNOWN, []byte{}, false, address.NewError(address.CashAddr, address.ErrInvalidVersion)
+	}
+
+	size := hashSizes[versionByte&0x07]
+
+	if len(data)-1 != size {
+		return address.UNKNOWN, []byte{}, false, address.NewError(address.CashAddr, address.ErrInvalidLength)
+	}
+
+	// A 256 bit script hash is a P2SH32 (OP_HASH256) output
+	if addrType == address.P2SH && size == 32 {
+		addrType = address.P2SH32
+	}
+
+	return addrType, data[1:], tokenAware, nil
+}
+
 /**
  * Decode a cashaddr string.
  */
@@ -234,7 +299,7 @@ func DecodeCashAddress(str string) (string, []byte, error) {
 		if c >= '0' && c <= '9' {
 			// We cannot have numbers in the prefix.
 			if prefixSize == 0 {
-				return "", []byte{}, errors.New("Addresses cannot have numbers in the prefix")
+				return "", []byte{}, address.NewPositionError(address.CashAddr, address.ErrInvalidPrefix, i)
 			}
 
 			continue
@@ -244,7 +309,7 @@ func DecodeCashAddress(str string) (string, []byte, error) {
 			// The separator must not be the first character, and there must not
 			// be 2 separators.
 			if i == 0 || prefixSize != 0 {
-				return "", []byte{}, errors.New("The separator must not be the first character")
+				return "", []byte{}, address.NewPositionError(address.CashAddr, address.ErrInvalidFormat, i)
 			}
 
 			prefixSize = i
@@ -252,17 +317,17 @@ func DecodeCashAddress(str string) (string, []byte, error) {
 		}
 
 		// We have an unexpected character.
-		return "", []byte{}, errors.New("Unexpected character")
+		return "", []byte{}, address.NewPositionError(address.CashAddr, address.ErrInvalidCharacter, i)
 	}
 
 	// We must have a prefix and a data part and we can't have both uppercase
 	// and lowercase.
 	if prefixSize == 0 {
-		return "", []byte{}, errors.New("Address must have a prefix")
+		return "", []byte{}, address.NewError(address.CashAddr, address.ErrInvalidPrefix)
 	}
 
 	if upper && lower {
-		return "", []byte{}, errors.New("Addresses cannot use both upper and lower case characters")
+		return "", []byte{}, address.NewError(address.CashAddr, address.ErrMixedCase)
 	}
 
 	// Get the prefix.
@@ -278,15 +343,20 @@ func DecodeCashAddress(str string) (string, []byte, error) {
 		c := byte(str[i+prefixSize+1])
 		// We have an invalid char in there.
 		if c > 127 || CHARSET_REVERSED[c] == -1 {
-			return "", []byte{}, errors.New("Invalid character")
+			return "", []byte{}, address.NewPositionError(address.CashAddr, address.ErrInvalidCharacter, i+prefixSize+1)
 		}
 
 		values[i] = byte(CHARSET_REVERSED[c])
 	}
 
+	// The payload must at least hold the version byte and the checksum
+	if valuesSize < 10 {
+		return "", []byte{}, address.NewError(address.CashAddr, address.ErrInvalidLength)
+	}
+
 	// Verify the checksum.
 	if !VerifyChecksum(prefix, values) {
-		return "", []byte{}, errors.New("Inavlid checksum")
+		return "", []byte{}, address.NewError(address.CashAddr, address.ErrInvalidChecksum)
 	}
 
 	return prefix, values[:len(values)-8], nil
@@ -317,7 +387,7 @@ func convertBits(data []byte, fromBits uint, tobits uint, pad bool) ([]byte, err
 			ret = append(ret, (acc<<(tobits-bits))&maxv)
 		}
 	} else if bits >= fromBits || ((acc<<(tobits-bits))&maxv) != 0 {
-		return []byte{}, errors.New("encoding padding error")
+		return []byte{}, address.ErrInvalidPadding
 	}
 	var dataArr []byte
 	for _, i := range ret {
@@ -2,6 +2,8 @@ package cashaddr
 
 import (
 	"encoding/hex"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/coinhako/addrconv/address"
@@ -15,7 +17,10 @@ func TestCheckEncodeCashAddress(t *testing.T) {
 	if err != nil {
 		t.Errorf("Error decoding hex: %s", err)
 	}
-	encodedAddress := CheckEncodeCashAddress(script, "bitcoincash", address.P2PKH)
+	encodedAddress, err := CheckEncodeCashAddress(script, "bitcoincash", address.P2PKH)
+	if err != nil {
+		t.Errorf("Error encoding address: %s", err)
+	}
 
 	if encodedAddress != "bitcoincash:qpha88vmhd36l69d8s8vnp9uzqdqkk5g6cnfvrsf5l" {
 		t.Errorf("Incorrect address. Expected %s, got %s", "bitcoincash:qpha88vmhd36l69d8s8vnp9uzqdqkk5g6cnfvrsf5l", encodedAddress)
@@ -25,9 +30,183 @@ func TestCheckEncodeCashAddress(t *testing.T) {
 	if err != nil {
 		t.Errorf("Error decoding hex: %s", err)
 	}
-	encodedAddress = CheckEncodeCashAddress(script, "bitcoincash", address.P2SH)
+	encodedAddress, err = CheckEncodeCashAddress(script, "bitcoincash", address.P2SH)
+	if err != nil {
+		t.Errorf("Error encoding address: %s", err)
+	}
 
 	if encodedAddress != "bitcoincash:pp9w7eldv8fer4hnmxgratvj8pkpalyey5qym9j8x5" {
 		t.Errorf("Incorrect address. Expected %s, got %s", "bitcoincash:pp9w7eldv8fer4hnmxgratvj8pkpalyey5qym9j8x5", encodedAddress)
 	}
 }
+
+func TestCheckDecodeCashAddressSizes(t *testing.T) {
+	var hashes = []string{
+		"f5bf48b397dae70be82b3cca4793f8eb2b6cdac9",
+		"7adbf6c17084bc86c1706827b41a56f5ca32865925e946ea",
+		"3a84f9cf51aae98a3bb3a78bf16a6183790b18719126325bfc0c075b",
+		"3173ef6623c6b48ffd1a3dcc0cc6489b0a07bb47a37f47cfef4fe69de825c060",
+		"c07138323e00fa4fc122d3b85b9628ea810b3f381706385e289b0b25631197d194b5c238beb136fb",
+		"e361ca9a7f99107c17a622e047e3745d3e19cf804ed63c5c40c6ba763696b98241223d8ce62ad48d863f4cb18c930e4c",
+		"d9fa7c4c6ef56dc4ff423baae6d495dbff663d034a72d1dc7d52cbfe7d1e6858f9d523ac0a7a5c34077638e4dd1a701bd017842789982041",
+		"d0f346310d5513d9e01e299978624ba883e6bda8f4c60883c10f28c2967e67ec77ecc7eeeaeafc6da89fad72d11ac961e164678b868aeec5f2c1da08884175b5",
+	}
+	var types = []address.AddressType{address.P2PKH, address.P2SH}
+
+	for _, v := range hashes {
+		hash, err := hex.DecodeString(v)
+		if err != nil {
+			t.Errorf("Error decoding hex: %s", err)
+		}
+
+		for _, addrType := range types {
+			encodedAddress, err := CheckEncodeCashAddress(hash, "bitcoincash", addrType)
+			if err != nil {
+				t.Errorf("Error encoding address: %s", err)
+			}
+
+			decodedAddress, err := CheckDecodeCashAddress(encodedAddress)
+			if err != nil {
+				t.Errorf("Error decoding address %s: %s", encodedAddress, err)
+				continue
+			}
+
+			if hex.EncodeToString(decodedAddress.Hash) != v {
+				t.Errorf("Incorrect hash for %s. Expected %s, got %x", encodedAddress, v, decodedAddress.Hash)
+			}
+
+			expectedType := addrType
+			if addrType == address.P2SH && len(hash) == 32 {
+				expectedType = address.P2SH32
+			}
+
+			if decodedAddress.Type != expectedType {
+				t.Errorf("Incorrect address type for %s. Expected %d, got %d", encodedAddress, expectedType, decodedAddress.Type)
+			}
+		}
+	}
+
+	decodedAddress, err := CheckDecodeCashAddress("bitcoincash:qr6m7j9njldwwzlg9v7v53unlr4jkmx6eylep8ekg2")
+	if err != nil {
+		t.Errorf("Error decoding address: %s", err)
+	}
+
+	if hex.EncodeToString(decodedAddress.Hash) != hashes[0] {
+		t.Errorf("Incorrect hash. Expected %s, got %x", hashes[0], decodedAddress.Hash)
+	}
+}
+
+func TestCheckDecodeCashAddressInvalidVersion(t *testing.T) {
+	hash, _ := hex.DecodeString("f5bf48b397dae70be82b3cca4793f8eb2b6cdac9")
+
+	// reserved bit, unknown type 15 and a size that doesn't match the payload
+	var versionBytes = []byte{0x80, 0x78, 0x01}
+
+	for _, versionByte := range versionBytes {
+		payload, err := convertBits(append([]byte{versionByte}, hash...), 8, 5, true)
+		if err != nil {
+			t.Errorf("Error packing payload: %s", err)
+		}
+
+		encodedAddress := Encode("bitcoincash", payload)
+		_, err = CheckDecodeCashAddress(encodedAddress)
+		if err == nil {
+			t.Errorf("Expected error decoding %s with version byte %#x", encodedAddress, versionByte)
+		}
+	}
+}
+
+func TestTokenAwareCashAddress(t *testing.T) {
+	hash, _ := hex.DecodeString("fc916f213a3d7f1369313d5fa30f6168f9446a2d")
+
+	// CHIP-2022-02 test vectors
+	var tokenAware = []bool{false, true}
+	var addresses = []string{
+		"bitcoincash:qr7fzmep8g7h7ymfxy74lgc0v950j3r2959lhtxxsl",
+		"bitcoincash:zr7fzmep8g7h7ymfxy74lgc0v950j3r295z4y4gq0v",
+	}
+
+	for i, v := range addresses {
+		var encodedAddress string
+		var err error
+		if tokenAware[i] {
+			encodedAddress, err = CheckEncodeTokenAwareCashAddress(hash, "bitcoincash", address.P2PKH)
+		} else {
+			encodedAddress, err = CheckEncodeCashAddress(hash, "bitcoincash", address.P2PKH)
+		}
+		if err != nil {
+			t.Errorf("Error encoding address: %s", err)
+		}
+
+		if encodedAddress != v {
+			t.Errorf("Incorrect address. Expected %s, got %s", v, encodedAddress)
+		}
+
+		decodedAddress, err := CheckDecodeCashAddress(v)
+		if err != nil {
+			t.Errorf("Error decoding address %s: %s", v, err)
+		}
+
+		if decodedAddress.Type != address.P2PKH || decodedAddress.TokenAware != tokenAware[i] {
+			t.Errorf("Incorrect address type for %s. Expected %d/%t, got %d/%t", v, address.P2PKH, tokenAware[i], decodedAddress.Type, decodedAddress.TokenAware)
+		}
+	}
+
+	encodedAddress, err := CheckEncodeTokenAwareCashAddress(hash, "bitcoincash", address.P2SH)
+	if err != nil {
+		t.Errorf("Error encoding address: %s", err)
+	}
+	if encodedAddress[:13] != "bitcoincash:r" {
+		t.Errorf("Incorrect token-aware P2SH address %s", encodedAddress)
+	}
+
+	decodedAddress, err := CheckDecodeCashAddress(encodedAddress)
+	if err != nil {
+		t.Errorf("Error decoding address %s: %s", encodedAddress, err)
+	}
+
+	if decodedAddress.Type != address.P2SH || !decodedAddress.TokenAware {
+		t.Errorf("Incorrect address type for %s. Expected %d/%t, got %d/%t", encodedAddress, address.P2SH, true, decodedAddress.Type, decodedAddress.TokenAware)
+	}
+}
+
+func TestSuggestCorrections(t *testing.T) {
+	validAddress := "bitcoincash:qr7fzmep8g7h7ymfxy74lgc0v950j3r2959lhtxxsl"
+
+	var addresses = []string{
+		"bitcoincash:qr7fzmep8g7h7ymfxy74lgc0v950j3r2959lhtxxsm",
+		"bitcoincash:qr7fzmep8g7h7ymfxy74lgd0v950j3r2959lhtxxsm",
+		"BITCOINCASH:QR7FZMEP8G7H7YMFXY74LGC0V950J3R2959LHTXXSM",
+	}
+	var positions = [][]int{{53}, {34, 53}, {53}}
+
+	for i, v := range addresses {
+		suggestions, err := SuggestCorrections(v)
+		if err != nil {
+			t.Errorf("Error suggesting corrections for %s: %s", v, err)
+			continue
+		}
+
+		if len(suggestions) != 1 {
+			t.Errorf("Incorrect number of suggestions for %s. Expected 1, got %d", v, len(suggestions))
+			continue
+		}
+
+		if suggestions[0].Address != validAddress && suggestions[0].Address != strings.ToUpper(validAddress) {
+			t.Errorf("Incorrect suggestion for %s. Expected %s, got %s", v, validAddress, suggestions[0].Address)
+		}
+
+		if !reflect.DeepEqual(suggestions[0].Positions, positions[i]) {
+			t.Errorf("Incorrect positions for %s. Expected %v, got %v", v, positions[i], suggestions[0].Positions)
+		}
+	}
+
+	suggestions, err := SuggestCorrections(validAddress)
+	if err != nil || suggestions != nil {
+		t.Errorf("Expected no suggestions for a valid address, got %v / %v", suggestions, err)
+	}
+
+	if _, err = SuggestCorrections("bitcoincash:qr7fzmep8g7h7ymfxy74lgc0v950j3r2959lhtxxsb"); err == nil {
+		t.Errorf("Expected error suggesting corrections for an invalid character")
+	}
+}
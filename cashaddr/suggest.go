@@ -0,0 +1,102 @@
+package cashaddr
+
+import (
+	"strings"
+
+	"github.com/coinhako/addrconv/address"
+)
+
+// Suggestion is a candidate correction for a cashaddr that failed its
+// checksum. It validates, but it is only a guess at what the user meant
+// and must be confirmed by them before it is used.
+type Suggestion struct {
+	Address   string // the corrected address
+	Positions []int  // indices of the characters that were substituted
+}
+
+// SuggestCorrections returns every address one or two character
+// substitutions away from input that passes CheckDecodeCashAddress.
+// The input must have a prefix and fail only on its checksum, otherwise
+// the decode error is returned.
+//
+// The checksum is a BCH code, and PolyMod is linear over GF(2), so the
+// checksum of the input is the sum of the contributions of each error.
+// Instead of trying every substitution we look for the one or two
+// position and value contributions that cancel it out.
+func SuggestCorrections(input string) ([]Suggestion, error) {
+	_, _, err := DecodeCashAddress(input)
+	if err == nil {
+		return nil, nil
+	}
+	if addrErr, ok := err.(*address.Error); !ok || addrErr.Err != address.ErrInvalidChecksum {
+		return nil, err
+	}
+
+	upper := strings.ToUpper(input) == input
+	prefix := strings.ToLower(input[:strings.Index(input, ":")])
+	payload := strings.ToLower(input[len(prefix)+1:])
+
+	values := ExpandPrefix(prefix)
+	offset := len(values)
+	for i := 0; i < len(payload); i++ {
+		values = append(values, byte(CHARSET_REVERSED[payload[i]]))
+	}
+	residue := PolyMod(values)
+
+	// contributions[p][e] is what xoring e into payload[p] does to PolyMod
+	contributions := make([][32]uint64, len(payload))
+	for p := range contributions {
+		for e := byte(1); e < 32; e++ {
+			values[offset+p] ^= e
+			contributions[p][e] = PolyMod(values) ^ residue
+			values[offset+p] ^= e
+		}
+	}
+
+	type substitution struct {
+		position int
+		value    byte
+	}
+	// Single substitutions first, then pairs
+	var candidates [][]substitution
+	byContribution := make(map[uint64][]substitution)
+	for p := range contributions {
+		for e := byte(1); e < 32; e++ {
+			if contributions[p][e] == residue {
+				candidates = append(candidates, []substitution{{p, e}})
+			}
+			byContribution[contributions[p][e]] = append(byContribution[contributions[p][e]], substitution{p, e})
+		}
+	}
+	for p := range contributions {
+		for e := byte(1); e < 32; e++ {
+			for _, other := range byContribution[residue^contributions[p][e]] {
+				if other.position > p {
+					candidates = append(candidates, []substitution{{p, e}, other})
+				}
+			}
+		}
+	}
+
+	var suggestions []Suggestion
+	for _, candidate := range candidates {
+		corrected := []byte(payload)
+		var positions []int
+		for _, s := range candidate {
+			corrected[s.position] = CHARSET[byte(CHARSET_REVERSED[payload[s.position]])^s.value]
+			positions = append(positions, len(prefix)+1+s.position)
+		}
+
+		correctedAddress := prefix + ":" + string(corrected)
+		if _, err := CheckDecodeCashAddress(correctedAddress); err != nil {
+			continue
+		}
+		if upper {
+			correctedAddress = strings.ToUpper(correctedAddress)
+		}
+
+		suggestions = append(suggestions, Suggestion{Address: correctedAddress, Positions: positions})
+	}
+
+	return suggestions, nil
+}
@@ -0,0 +1,165 @@
+package addrconv
+
+import (
+	"github.com/coinhako/addrconv/address"
+	"github.com/coinhako/addrconv/nulldata"
+	"github.com/coinhako/blockutils"
+)
+
+// FromScript classifies an output script into the address it pays to,
+// without picking a string encoding. Unlike Encode, P2PK outputs are
+// reported as P2PK with their raw public key, and their Hash holds the
+// hash160 of the key, so EncodeToBase58 can still give the P2PKH address
+// when that's wanted. Bare multisig outputs are reported as P2MS with
+// their threshold and keys, see PubKeyAddresses for their P2PKH
+// addresses.
+func (network Network) FromScript(script blockutils.Script) (decodedAddress address.Address, err error) {
+	if pubKey, ok := parseP2PKScript(script); ok {
+		decodedAddress.Type = address.P2PK
+		decodedAddress.PubKeys = [][]byte{pubKey}
+		decodedAddress.Hash = blockutils.Hash160(pubKey)
+		decodedAddress.Version = network.PubKeyPrefix
+		return decodedAddress, nil
+	}
+
+	if threshold, pubKeys, ok := parseMultisigScript(script); ok {
+		decodedAddress.Type = address.P2MS
+		decodedAddress.Threshold = threshold
+		decodedAddress.PubKeys = pubKeys
+		return decodedAddress, nil
+	}
+
+	switch {
+	case isP2SH32(script):
+		decodedAddress.Type = address.P2SH32
+		decodedAddress.Hash = script[2:34]
+		decodedAddress.CashAddrPrefix = network.CashAddrPrefix
+		return decodedAddress, nil
+
+	case script.IsP2PKH():
+		decodedAddress.Type = address.P2PKH
+		decodedAddress.Hash = script[3:23]
+		decodedAddress.Version = network.PubKeyPrefix
+		decodedAddress.CashAddrPrefix = network.CashAddrPrefix
+		return decodedAddress, nil
+
+	case script.IsP2SH():
+		decodedAddress.Type = address.P2SH
+		decodedAddress.Hash = script[2:22]
+		decodedAddress.Version = network.ScriptHashPrefix
+		decodedAddress.CashAddrPrefix = network.CashAddrPrefix
+		return decodedAddress, nil
+	}
+
+	if witnessVersion, witnessProgram, ok := parseWitnessScript(script); ok {
+		switch {
+		case witnessVersion == 0 && len(witnessProgram) == 20:
+			decodedAddress.Type = address.P2WPKH
+		case witnessVersion == 0 && len(witnessProgram) == 32:
+			decodedAddress.Type = address.P2WSH
+		case witnessVersion == 1 && len(witnessProgram) == 32:
+			decodedAddress.Type = address.P2TR
+		default:
+			return decodedAddress, address.NewError(address.NoEncoding, address.ErrUnknownAddressType)
+		}
+		decodedAddress.Hash = witnessProgram
+		decodedAddress.Bech32HRP = network.Bech32Prefix
+		return decodedAddress, nil
+	}
+
+	return decodedAddress, address.NewError(address.NoEncoding, address.ErrUnknownAddressType)
+}
+
+// Largest OP_RETURN output script Standard accepts for networks that
+// don't set their own limit. This is the -datacarriersize default of
+// Bitcoin Core before v30, kept as a conservative policy since older
+// nodes still won't relay anything bigger.
+const defaultMaxNullDataSize = 83
+
+// EncodeResult describes what an output script pays to
+type EncodeResult struct {
+	Address  address.Address
+	Encoded  string           // address string, empty if the output has none
+	Encoding address.Encoding // encoding of Encoded, NoEncoding if empty
+	Network  Network
+	Standard bool
+
+	NullData *nulldata.Payload // only set for NULL_DATA outputs that parse
+}
+
+// EncodeScript classifies an output script and encodes its address.
+// Unlike Encode, it never returns a hex script in place of an address:
+// null data outputs are reported as NULL_DATA with their payload,
+// unrecognised scripts as NONSTANDARD, and outputs that have no address
+// on the network, such as segwit outputs on networks without bech32, are
+// returned without an address string. P2PK and bare multisig outputs have
+// no address string either, see PubKeyAddresses.
+func (network Network) EncodeScript(script blockutils.Script) (result EncodeResult, err error) {
+	result.Network = network
+
+	if len(script) > 0 && script[0] == opReturn {
+		result.Address.Type = address.NULL_DATA
+		payload, err := nulldata.Parse(script)
+		if err == nil {
+			result.NullData = payload
+			result.Standard = len(script) <= network.maxNullDataSize()
+		}
+		return result, nil
+	}
+
+	decodedAddress, err := network.FromScript(script)
+	if err != nil {
+		if witnessVersion, witnessProgram, ok := parseWitnessScript(script); ok && witnessVersion > 0 {
+			result.Address.Type = address.WITNESS_UNKNOWN
+			result.Address.Hash = witnessProgram
+			result.Address.Bech32HRP = network.Bech32Prefix
+			return network.encodeResult(result, script, address.Bech32m, network.SupportsBech32())
+		}
+		result.Address.Type = address.NONSTANDARD
+		return result, nil
+	}
+	result.Address = decodedAddress
+
+	switch decodedAddress.Type {
+	case address.P2PK:
+		result.Standard = true
+	case address.P2MS:
+		// Bitcoin Core only relays bare multisig with up to 3 keys
+		result.Standard = len(decodedAddress.PubKeys) <= 3
+	case address.P2PKH, address.P2SH:
+		return network.encodeResult(result, script, address.Base58, true)
+	case address.P2SH32:
+		return network.encodeResult(result, script, address.CashAddr, network.SupportsCashAddr())
+	case address.P2WPKH, address.P2WSH:
+		return network.encodeResult(result, script, address.Bech32, network.SupportsBech32())
+	case address.P2TR:
+		return network.encodeResult(result, script, address.Bech32m, network.SupportsBech32())
+	}
+
+	return result, nil
+}
+
+// encodeResult fills in the address string, if the network has an
+// encoding for it
+func (network Network) encodeResult(result EncodeResult, script blockutils.Script, encoding address.Encoding, supported bool) (EncodeResult, error) {
+	if !supported {
+		return result, nil
+	}
+
+	encodedAddress, err := network.Encode(script)
+	if err != nil {
+		return result, err
+	}
+
+	result.Encoded = encodedAddress
+	result.Encoding = encoding
+	result.Standard = true
+	return result, nil
+}
+
+func (network Network) maxNullDataSize() int {
+	if network.MaxNullDataSize == 0 {
+		return defaultMaxNullDataSize
+	}
+	return network.MaxNullDataSize
+}
@@ -0,0 +1,151 @@
+package addrconv
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/coinhako/addrconv/address"
+)
+
+// AddressFormat is a string format ConvertAddress can produce
+type AddressFormat int
+
+const (
+	LegacyFormat           AddressFormat = 0 // base58
+	CashAddrFormat         AddressFormat = 1 // lowercase cashaddr with prefix
+	CashAddrNoPrefixFormat AddressFormat = 2 // lowercase cashaddr without prefix
+	CashAddrUpperFormat    AddressFormat = 3 // uppercase cashaddr with prefix, for QR codes
+)
+
+// ErrUnknownFormat is returned by ConvertAddress for an AddressFormat
+// that isn't one of the constants above
+var ErrUnknownFormat = errors.New("unknown address format")
+
+// ConvertAddress decodes an address in any format the network supports
+// and encodes it again in the target format, keeping its type. Only
+// P2PKH and P2SH addresses have a legacy format, so P2SH32 and
+// token-aware addresses can only be converted between cashaddr formats.
+func (network Network) ConvertAddress(encodedAddress string, format AddressFormat) (string, error) {
+	if format < LegacyFormat || format > CashAddrUpperFormat {
+		return "", ErrUnknownFormat
+	}
+
+	decodedAddress, err := network.Decode(encodedAddress)
+	if err != nil {
+		return "", err
+	}
+
+	if format == LegacyFormat {
+		if decodedAddress.TokenAware {
+			return "", address.NewError(address.Base58, address.ErrUnsupportedType)
+		}
+		return network.EncodeToBase58(decodedAddress)
+	}
+
+	converted, err := network.EncodeToCashAddr(decodedAddress)
+	if err != nil {
+		return "", err
+	}
+
+	switch format {
+	case CashAddrNoPrefixFormat:
+		return strings.TrimPrefix(converted, network.CashAddrPrefix+":"), nil
+	case CashAddrUpperFormat:
+		return strings.ToUpper(converted), nil
+	}
+
+	return converted, nil
+}
+
+// ToCurrentPrefix converts an address using a deprecated version byte,
+// such as a Litecoin P2SH address starting with 3, to the network's
+// current version byte. Other base58 addresses are returned unchanged.
+func (network Network) ToCurrentPrefix(encodedAddress string) (string, error) {
+	decodedAddress, err := network.decodeBase58(encodedAddress)
+	if err != nil {
+		return "", err
+	}
+
+	return network.EncodeToBase58(decodedAddress)
+}
+
+// RemappedAddress is an address moved to another network, in every
+// format the network supports. Formats the address has no form in are
+// left empty.
+type RemappedAddress struct {
+	Address  address.Address
+	Base58   string // P2PKH and P2SH with a hash160, unless token-aware
+	Bech32   string // segwit types, bech32 or bech32m by witness version
+	CashAddr string // P2PKH, P2SH and P2SH32 on cashaddr networks, token-aware if the source was
+}
+
+// RemapAddress decodes an address on the source network and encodes the
+// same hash for the target network, for recovering funds sent on the
+// wrong chain. Token-aware addresses keep their token awareness, so they
+// only get a cashaddr. Addresses the target has no encoding for at all,
+// such as segwit addresses on Dogecoin, return ErrUnsupportedEncoding.
+func RemapAddress(encodedAddress string, source, target Network) (RemappedAddress, error) {
+	decodedAddress, err := source.Decode(encodedAddress)
+	if err != nil {
+		return RemappedAddress{}, err
+	}
+
+	remapped := RemappedAddress{
+		Address: address.Address{
+			Type:       decodedAddress.Type,
+			Hash:       decodedAddress.Hash,
+			TokenAware: decodedAddress.TokenAware,
+		},
+	}
+
+	switch remapped.Address.Type {
+	case address.P2PKH, address.P2SH:
+		if remapped.Address.Type == address.P2PKH {
+			remapped.Address.Version = target.PubKeyPrefix
+		} else {
+			remapped.Address.Version = target.ScriptHashPrefix
+		}
+		if !remapped.Address.TokenAware && len(remapped.Address.Hash) == 20 {
+			remapped.Base58, err = target.EncodeToBase58(remapped.Address)
+			if err != nil {
+				return RemappedAddress{}, err
+			}
+		}
+	case address.P2SH32:
+	case address.P2WPKH, address.P2WSH, address.P2TR:
+		if !target.SupportsBech32() {
+			encoding := address.Bech32
+			if remapped.Address.Type == address.P2TR {
+				encoding = address.Bech32m
+			}
+			return RemappedAddress{}, address.NewError(encoding, address.ErrUnsupportedEncoding)
+		}
+		remapped.Address.Bech32HRP = target.Bech32Prefix
+		script, err := target.ToScript(remapped.Address)
+		if err != nil {
+			return RemappedAddress{}, err
+		}
+		remapped.Bech32, err = target.Encode(script)
+		if err != nil {
+			return RemappedAddress{}, err
+		}
+		return remapped, nil
+	default:
+		return RemappedAddress{}, address.NewError(address.NoEncoding, address.ErrUnsupportedType)
+	}
+
+	if target.SupportsCashAddr() {
+		remapped.Address.CashAddrPrefix = target.CashAddrPrefix
+		remapped.CashAddr, err = target.EncodeToCashAddr(remapped.Address)
+		if err != nil {
+			return RemappedAddress{}, err
+		}
+	}
+
+	// P2SH32, token-aware and longer hash addresses only have a cashaddr
+	if remapped.Base58 == "" && remapped.CashAddr == "" {
+		return RemappedAddress{}, address.NewError(address.CashAddr, address.ErrUnsupportedEncoding)
+	}
+
+	return remapped, nil
+}
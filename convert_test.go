@@ -0,0 +1,179 @@
+package addrconv
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/coinhako/addrconv/address"
+	"github.com/coinhako/addrconv/cashaddr"
+)
+
+func TestConvertAddress(t *testing.T) {
+	var inputs = []string{
+		"1BpEi6DfDAUFd7GtittLSdBeYJvcoaVggu",
+		"bitcoincash:qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a",
+		"3CWFddi6m4ndiGyKqzYvsFYagqDLPVMTzC",
+		"PPM2QSZNHKS23Z7629MMS6S4CWEF74VCWVN0H829PQ",
+	}
+	var formats = []AddressFormat{LegacyFormat, CashAddrFormat, CashAddrNoPrefixFormat, CashAddrUpperFormat}
+	// Each pair of inputs is the same address, in each format
+	var outputs = [][]string{
+		{
+			"1BpEi6DfDAUFd7GtittLSdBeYJvcoaVggu",
+			"bitcoincash:qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a",
+			"qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a",
+			"BITCOINCASH:QPM2QSZNHKS23Z7629MMS6S4CWEF74VCWVY22GDX6A",
+		},
+		{
+			"3CWFddi6m4ndiGyKqzYvsFYagqDLPVMTzC",
+			"bitcoincash:ppm2qsznhks23z7629mms6s4cwef74vcwvn0h829pq",
+			"ppm2qsznhks23z7629mms6s4cwef74vcwvn0h829pq",
+			"BITCOINCASH:PPM2QSZNHKS23Z7629MMS6S4CWEF74VCWVN0H829PQ",
+		},
+	}
+
+	for i, v := range inputs {
+		expected := outputs[i/2]
+		for j, format := range formats {
+			converted, err := BitcoinCashNetwork.ConvertAddress(v, format)
+			if err != nil {
+				t.Errorf("Error converting %s: %s", v, err)
+				continue
+			}
+
+			if converted != expected[j] {
+				t.Errorf("Incorrect conversion of %s. Expected %s, got %s", v, expected[j], converted)
+			}
+		}
+	}
+
+	// Types legacy addresses can't carry
+	var cashOnly = []string{
+		"bitcoincash:pvqqqqqqqqqqqqqqqqqqqqqqzg69v7ysqqqqqqqqqqqqqqqqqqqqqpkp7fqn0",
+		"bitcoincash:zpm2qsznhks23z7629mms6s4cwef74vcwvrqekrq9w",
+	}
+	for _, v := range cashOnly {
+		if _, err := BitcoinCashNetwork.ConvertAddress(v, LegacyFormat); !errors.Is(err, address.ErrUnsupportedType) {
+			t.Errorf("Expected ErrUnsupportedType converting %s to legacy, got %v", v, err)
+		}
+	}
+
+	// cashaddr hashes longer than 20 bytes have no base58 form
+	longHash, _ := cashaddr.CheckEncodeCashAddress(make([]byte, 24), "bitcoincash", address.P2PKH)
+	if _, err := BitcoinCashNetwork.ConvertAddress(longHash, LegacyFormat); !errors.Is(err, address.ErrInvalidLength) {
+		t.Errorf("Expected ErrInvalidLength converting %s to legacy, got %v", longHash, err)
+	}
+	if converted, err := BitcoinCashNetwork.ConvertAddress(longHash, CashAddrNoPrefixFormat); err != nil || "bitcoincash:"+converted != longHash {
+		t.Errorf("Incorrect conversion of %s, got %s / %v", longHash, converted, err)
+	}
+
+	if _, err := BitcoinNetwork.ConvertAddress(inputs[0], CashAddrFormat); !errors.Is(err, address.ErrUnsupportedEncoding) {
+		t.Errorf("Expected ErrUnsupportedEncoding on bitcoin, got %v", err)
+	}
+
+	if _, err := BitcoinCashNetwork.ConvertAddress("bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", CashAddrFormat); err == nil {
+		t.Errorf("Expected error converting a bitcoin address on bitcoin cash")
+	}
+
+	// Unknown formats are rejected before the address is looked at
+	for _, format := range []AddressFormat{-1, 4} {
+		if _, err := BitcoinCashNetwork.ConvertAddress("invalid", format); err != ErrUnknownFormat {
+			t.Errorf("Expected ErrUnknownFormat for format %d, got %v", format, err)
+		}
+	}
+}
+
+func TestLegacyScriptHashPrefix(t *testing.T) {
+	// The same script hash, with the old bitcoin style 0x05 version byte
+	legacyAddress := "3CWFddi6m4ndiGyKqzYvsFYagqDLPVMTzC"
+	var networks = []Network{LitecoinNetwork, DigibyteNetwork}
+	var prefixes = []string{"M", "S"}
+
+	for i, network := range networks {
+		decodedAddress, err := network.Decode(legacyAddress)
+		if err != nil {
+			t.Errorf("Error decoding %s on %s: %s", legacyAddress, network.Name, err)
+			continue
+		}
+
+		if decodedAddress.Type != address.P2SH || !decodedAddress.LegacyPrefix {
+			t.Errorf("Expected a legacy prefix P2SH address on %s, got %d / %t", network.Name, decodedAddress.Type, decodedAddress.LegacyPrefix)
+		}
+
+		converted, err := network.ToCurrentPrefix(legacyAddress)
+		if err != nil {
+			t.Errorf("Error converting %s on %s: %s", legacyAddress, network.Name, err)
+			continue
+		}
+
+		if converted[:1] != prefixes[i] {
+			t.Errorf("Incorrect prefix on %s. Expected %s, got %s", network.Name, prefixes[i], converted)
+		}
+
+		currentAddress, err := network.Decode(converted)
+		if err != nil || currentAddress.LegacyPrefix || currentAddress.Version != network.ScriptHashPrefix || string(currentAddress.Hash) != string(decodedAddress.Hash) {
+			t.Errorf("Incorrect conversion %s on %s: %v", converted, network.Name, err)
+		}
+
+		// Current addresses are left alone
+		if same, err := network.ToCurrentPrefix(converted); err != nil || same != converted {
+			t.Errorf("Expected %s unchanged, got %s / %v", converted, same, err)
+		}
+	}
+
+	if _, err := DogecoinNetwork.Decode(legacyAddress); err == nil {
+		t.Errorf("Expected error decoding %s on dogecoin", legacyAddress)
+	}
+}
+
+func TestRemapAddress(t *testing.T) {
+	var inputs = []string{
+		"1BpEi6DfDAUFd7GtittLSdBeYJvcoaVggu",
+		"bitcoincash:ppm2qsznhks23z7629mms6s4cwef74vcwvn0h829pq",
+		"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4",
+		"bitcoincash:zr7fzmep8g7h7ymfxy74lgc0v950j3r295z4y4gq0v",
+	}
+	var sources = []Network{BitcoinNetwork, BitcoinCashNetwork, BitcoinNetwork, BitcoinCashNetwork}
+	var targets = []Network{BitcoinCashNetwork, LitecoinNetwork, LitecoinNetwork, BitcoinCashNetwork}
+	var base58s = []string{"1BpEi6DfDAUFd7GtittLSdBeYJvcoaVggu", "MJiPwX84iBe4WnFDwsYGgtnz1XonPhUqhf", "", ""}
+	var bech32s = []string{"", "", "ltc1qw508d6qejxtdg4y5r3zarvary0c5xw7kgmn4n9", ""}
+	var cashAddrs = []string{
+		"bitcoincash:qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a",
+		"",
+		"",
+		"bitcoincash:zr7fzmep8g7h7ymfxy74lgc0v950j3r295z4y4gq0v",
+	}
+
+	for i, v := range inputs {
+		remapped, err := RemapAddress(v, sources[i], targets[i])
+		if err != nil {
+			t.Errorf("Error remapping %s to %s: %s", v, targets[i].Name, err)
+			continue
+		}
+
+		if remapped.Base58 != base58s[i] {
+			t.Errorf("Incorrect base58 address. Expected %s, got %s", base58s[i], remapped.Base58)
+		}
+
+		if remapped.Bech32 != bech32s[i] {
+			t.Errorf("Incorrect bech32 address. Expected %s, got %s", bech32s[i], remapped.Bech32)
+		}
+
+		if remapped.CashAddr != cashAddrs[i] {
+			t.Errorf("Incorrect cashaddr. Expected %s, got %s", cashAddrs[i], remapped.CashAddr)
+		}
+	}
+
+	var unmapped = []string{
+		"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4",
+		"bitcoincash:pvqqqqqqqqqqqqqqqqqqqqqqzg69v7ysqqqqqqqqqqqqqqqqqqqqqpkp7fqn0",
+		"bitcoincash:zr7fzmep8g7h7ymfxy74lgc0v950j3r295z4y4gq0v",
+	}
+	var unmappedSources = []Network{BitcoinNetwork, BitcoinCashNetwork, BitcoinCashNetwork}
+	var unmappedTargets = []Network{DogecoinNetwork, BitcoinNetwork, BitcoinNetwork}
+	for i, v := range unmapped {
+		if _, err := RemapAddress(v, unmappedSources[i], unmappedTargets[i]); !errors.Is(err, address.ErrUnsupportedEncoding) {
+			t.Errorf("Expected ErrUnsupportedEncoding remapping %s to %s, got %v", v, unmappedTargets[i].Name, err)
+		}
+	}
+}
@@ -1,11 +1,11 @@
 package addrconv
 
 import (
-	"errors"
 	"strings"
 
 	"github.com/coinhako/addrconv/address"
 	"github.com/coinhako/addrconv/base58"
+	"github.com/coinhako/addrconv/bech32"
 	"github.com/coinhako/addrconv/cashaddr"
 )
 
@@ -17,22 +17,160 @@ func (network Network) decodeByGuessingEncoding(encodedAddress string) (decodedA
 	// Let's try base58 first, since it's the most common, and more
 	// or less all networks support it
 
-	decodedAddress, err = base58.CheckDecode(encodedAddress)
-	if err == nil { // Decoding was successful, we're done
+	decodedAddress, base58Err := network.decodeBase58(encodedAddress)
+	if base58Err == nil { // Decoding was successful, we're done
 		return decodedAddress, nil
 	}
 
-	if network.SupportsCashAddr() {
-		encodedCashAddress := encodedAddress
-		if !strings.HasPrefix(encodedAddress, network.CashAddrPrefix+":") {
-			encodedCashAddress = network.CashAddrPrefix + ":" + encodedAddress
+	var bech32Err error
+	if network.SupportsBech32() {
+		decodedAddress, bech32Err = network.decodeSegwit(encodedAddress)
+		if bech32Err == nil {
+			return decodedAddress, nil
 		}
+	}
 
-		decodedAddress, err = cashaddr.CheckDecodeCashAddress(encodedCashAddress)
-		if err == nil {
+	var cashAddrErr error
+	if network.SupportsCashAddr() {
+		decodedAddress, cashAddrErr = network.decodeCashAddr(encodedAddress)
+		if cashAddrErr == nil {
 			return decodedAddress, nil
 		}
 	}
 
-	return decodedAddress, errors.New("Unknown address type")
+	// Nothing decoded, so report the error from the encoding the
+	// address looks like it was meant to be in
+	switch {
+	case bech32Err != nil && strings.HasPrefix(strings.ToLower(encodedAddress), network.Bech32Prefix+"1"):
+		return decodedAddress, bech32Err
+	case cashAddrErr != nil && looksLikeCashAddr(encodedAddress):
+		return decodedAddress, cashAddrErr
+	case len(encodedAddress) > 0:
+		return decodedAddress, base58Err
+	}
+
+	return decodedAddress, address.NewError(address.NoEncoding, address.ErrUnknownAddressType)
+}
+
+// looksLikeCashAddr checks for an explicit prefix, or a string that is
+// too long for base58 but long enough for the smallest cashaddr payload
+func looksLikeCashAddr(encodedAddress string) bool {
+	return strings.Contains(encodedAddress, ":") || len(encodedAddress) >= 42
+}
+
+func (network Network) decodeBase58(encodedAddress string) (decodedAddress address.Address, err error) {
+	decodedAddress, err = base58.CheckDecode(encodedAddress)
+	if err != nil {
+		return decodedAddress, err
+	}
+
+	if len(decodedAddress.Hash) != 20 {
+		return decodedAddress, address.NewError(address.Base58, address.ErrInvalidLength)
+	}
+
+	// Networks reuse version bytes for different purposes, so
+	// classify the version byte against this network only
+	switch decodedAddress.Version {
+	case network.PubKeyPrefix:
+		decodedAddress.Type = address.P2PKH
+	case network.ScriptHashPrefix:
+		decodedAddress.Type = address.P2SH
+	default:
+		if !network.isLegacyScriptHashPrefix(decodedAddress.Version) {
+			return decodedAddress, address.NewError(address.Base58, address.ErrInvalidVersion)
+		}
+		decodedAddress.Type = address.P2SH
+		decodedAddress.LegacyPrefix = true
+	}
+
+	return decodedAddress, nil
+}
+
+func (network Network) decodeSegwit(encodedAddress string) (decodedAddress address.Address, err error) {
+	witnessVersion, witnessProgram, err := bech32.SegwitAddrDecode(network.Bech32Prefix, encodedAddress)
+	if err != nil {
+		// Only look for the mistyped characters once the address is
+		// known to be meant for this network
+		addrErr, ok := err.(*address.Error)
+		if ok && addrErr.Err == address.ErrInvalidChecksum && strings.HasPrefix(strings.ToLower(encodedAddress), network.Bech32Prefix+"1") {
+			if positions, encoding := bech32.LocateErrors(encodedAddress); positions != nil {
+				addrErr.ErrorPositions = positions
+				addrErr.Encoding = address.Bech32
+				if encoding == bech32.Bech32m {
+					addrErr.Encoding = address.Bech32m
+				}
+			}
+		}
+		return decodedAddress, err
+	}
+
+	switch {
+	case witnessVersion == 0 && len(witnessProgram) == 20:
+		decodedAddress.Type = address.P2WPKH
+	case witnessVersion == 0 && len(witnessProgram) == 32:
+		decodedAddress.Type = address.P2WSH
+	case witnessVersion == 1 && len(witnessProgram) == 32:
+		decodedAddress.Type = address.P2TR
+	case witnessVersion == 0:
+		return decodedAddress, address.NewError(address.Bech32, address.ErrInvalidLength)
+	default:
+		return decodedAddress, address.NewError(address.Bech32m, address.ErrUnknownAddressType)
+	}
+
+	decodedAddress.Hash = toByteSlice(witnessProgram)
+	decodedAddress.Bech32HRP = network.Bech32Prefix
+
+	return decodedAddress, nil
+}
+
+func (network Network) decodeCashAddr(encodedAddress string) (decodedAddress address.Address, err error) {
+	if separator := strings.LastIndex(encodedAddress, ":"); separator >= 0 {
+		// An explicit prefix must belong to this network, other chains
+		// use the same encoding with their own prefix
+		prefix := strings.ToLower(encodedAddress[:separator])
+		for _, allowed := range network.cashAddrPrefixes() {
+			if prefix == allowed {
+				return cashaddr.CheckDecodeCashAddress(encodedAddress)
+			}
+		}
+		return decodedAddress, address.NewError(address.CashAddr, address.ErrInvalidPrefix)
+	}
+
+	// Without a prefix the checksum only verifies against the right
+	// one, so try them all and report the error for the main prefix
+	for i, prefix := range network.cashAddrPrefixes() {
+		prefixedAddress, prefixErr := decodePrefixlessCashAddr(encodedAddress, prefix)
+		if prefixErr == nil {
+			return prefixedAddress, nil
+		}
+		if i == 0 {
+			err = prefixErr
+		}
+	}
+
+	return decodedAddress, err
+}
+
+func decodePrefixlessCashAddr(encodedAddress string, prefix string) (decodedAddress address.Address, err error) {
+	// Uppercase addresses need an uppercase prefix, or they'd be
+	// rejected as mixed case
+	if encodedAddress == strings.ToUpper(encodedAddress) {
+		prefix = strings.ToUpper(prefix)
+	}
+
+	decodedAddress, err = cashaddr.CheckDecodeCashAddress(prefix + ":" + encodedAddress)
+	if addrErr, ok := err.(*address.Error); ok && addrErr.Position >= 0 {
+		// Report positions in the string we were given
+		addrErr.Position -= len(prefix) + 1
+	}
+
+	return decodedAddress, err
+}
+
+func toByteSlice(vals []int) []byte {
+	buf := make([]byte, len(vals))
+	for i := 0; i < len(buf); i++ {
+		buf[i] = byte(vals[i])
+	}
+	return buf
 }
@@ -1,9 +1,6 @@
 package addrconv
 
 import (
-	"errors"
-	"fmt"
-
 	"github.com/coinhako/addrconv/address"
 	"github.com/coinhako/addrconv/base58"
 	"github.com/coinhako/addrconv/bech32"
@@ -16,18 +13,23 @@ func (network Network) Encode(script blockutils.Script) (string, error) {
 		return script.String(), nil
 	}
 
-	if script.IsP2PK() {
-		hash160, err := script.P2PKHash160()
-		if err != nil {
-			return script.String(), err
+	if isP2SH32(script) {
+		if !network.SupportsCashAddr() {
+			return script.String(), nil
 		}
-		return base58.CheckEncode(hash160, network.PubKeyPrefix), nil
+		return cashaddr.CheckEncodeCashAddress(script[2:34], network.CashAddrPrefix, address.P2SH32)
+	}
+
+	// P2PK outputs are shown as the P2PKH address of the key, like block
+	// explorers do. Use FromScript to tell them apart from real P2PKH.
+	if pubKey, ok := parseP2PKScript(script); ok {
+		return base58.CheckEncode(blockutils.Hash160(pubKey), network.PubKeyPrefix), nil
 	}
 
 	if script.IsP2PKH() {
 		hash160, err := script.P2PKHHash160()
 		if err != nil {
-			return script.String(), err
+			return script.String(), address.NewError(address.Base58, address.ErrInvalidScript)
 		}
 		return base58.CheckEncode(hash160, network.PubKeyPrefix), nil
 	}
@@ -35,31 +37,74 @@ func (network Network) Encode(script blockutils.Script) (string, error) {
 	if script.IsP2SH() {
 		hash160, err := script.P2SHHash160()
 		if err != nil {
-			return script.String(), err
+			return script.String(), address.NewError(address.Base58, address.ErrInvalidScript)
 		}
 		return base58.CheckEncode(hash160, network.ScriptHashPrefix), nil
 	}
 
-	if script.IsWitnessScript() {
-		witnessVersion, err := script.WitnessVersion()
-		if err != nil {
-			return script.String(), err
-		}
-		intWitnessVersion := int(witnessVersion)
-		witnessProgram, err := script.WitnessProgram()
-		if err != nil {
-			return script.String(), err
+	if witnessVersion, witnessProgram, ok := parseWitnessScript(script); ok {
+		if !network.SupportsBech32() {
+			return script.String(), address.NewError(address.Bech32, address.ErrUnsupportedEncoding)
 		}
 		intWitnessProgram, err := toIntSlice(witnessProgram)
 		if err != nil {
 			return script.String(), err
 		}
-		return bech32.SegwitAddrEncode(network.Bech32Prefix, intWitnessVersion, intWitnessProgram)
+		return bech32.SegwitAddrEncode(network.Bech32Prefix, witnessVersion, intWitnessProgram)
 	}
 
 	return script.String(), nil
 }
 
+// isP2SH32 checks for a BCH OP_HASH256 <32 bytes> OP_EQUAL output
+func isP2SH32(script blockutils.Script) bool {
+	return len(script) == 35 && script[0] == opHash256 && script[1] == 32 && script[34] == opEqual
+}
+
+// parseP2PKScript extracts the key from a <pubkey> OP_CHECKSIG output.
+// The key is only checked for a valid prefix, not for being on the curve,
+// since such outputs exist on chain either way. That includes 0x06 and
+// 0x07 hybrid keys, which Bitcoin Core also treats as P2PK.
+func parseP2PKScript(script blockutils.Script) (pubKey []byte, ok bool) {
+	switch {
+	case len(script) == 35 && script[0] == 33 && (script[1] == 0x02 || script[1] == 0x03):
+	case len(script) == 67 && script[0] == 65 && (script[1] == 0x04 || script[1] == 0x06 || script[1] == 0x07):
+	default:
+		return nil, false
+	}
+
+	if script[len(script)-1] != opCheckSig {
+		return nil, false
+	}
+
+	return script[1 : len(script)-1], true
+}
+
+// parseWitnessScript splits a segwit output script (a version opcode
+// followed by a single 2 to 40 byte push) into its witness version and
+// program. blockutils only recognises version 0, so taproot and future
+// witness versions are handled here.
+func parseWitnessScript(script blockutils.Script) (witnessVersion int, witnessProgram []byte, ok bool) {
+	if len(script) < 4 || len(script) > 42 {
+		return 0, nil, false
+	}
+
+	switch {
+	case script[0] == 0x00:
+		witnessVersion = 0
+	case script[0] >= 0x51 && script[0] <= 0x60: // OP_1 to OP_16
+		witnessVersion = int(script[0] - 0x50)
+	default:
+		return 0, nil, false
+	}
+
+	if int(script[1]) != len(script)-2 {
+		return 0, nil, false
+	}
+
+	return witnessVersion, script[2:], true
+}
+
 func toIntSlice(buf []byte) ([]int, error) {
 	vals := make([]int, len(buf))
 	for i := 0; i < len(vals); i++ {
@@ -70,30 +115,66 @@ func toIntSlice(buf []byte) ([]int, error) {
 
 func (network Network) EncodeToBase58(decodedAddress address.Address) (string, error) {
 
+	// cashaddr allows longer hashes, but base58 addresses are always
+	// hash160s
+	if (decodedAddress.Type == address.P2PKH || decodedAddress.IsP2SH()) && len(decodedAddress.Hash) != 20 {
+		return "", address.NewError(address.Base58, address.ErrInvalidLength)
+	}
+
 	if decodedAddress.Type == address.P2PKH {
 		return base58.CheckEncode(decodedAddress.Hash, network.PubKeyPrefix), nil
 	}
 
+	// A P2PK output has no address of its own, this is the P2PKH address
+	// of its key
+	if decodedAddress.Type == address.P2PK && len(decodedAddress.PubKeys) == 1 {
+		return base58.CheckEncode(blockutils.Hash160(decodedAddress.PubKeys[0]), network.PubKeyPrefix), nil
+	}
+
 	if decodedAddress.IsP2SH() {
 		return base58.CheckEncode(decodedAddress.Hash, network.ScriptHashPrefix), nil
 	}
 
-	return "", fmt.Errorf("Unknown address %d type for base58", decodedAddress.Type)
+	return "", address.NewError(address.Base58, address.ErrUnsupportedType)
 
 }
 
 func (network Network) EncodeToCashAddr(decodedAddress address.Address) (encodedAddress string, err error) {
 	if !network.SupportsCashAddr() {
-		err = errors.New("Network does not support cashaddr")
+		err = address.NewError(address.CashAddr, address.ErrUnsupportedEncoding)
 		return encodedAddress, err
 	}
 
-	if decodedAddress.Type != address.P2SH && decodedAddress.Type != address.P2PKH {
-		err = errors.New("cashaddr only supports P2SH and P2PKH addresses")
+	if decodedAddress.Type != address.P2SH && decodedAddress.Type != address.P2PKH && decodedAddress.Type != address.P2SH32 {
+		err = address.NewError(address.CashAddr, address.ErrUnsupportedType)
 		return encodedAddress, err
 	}
 
 	decodedAddress.CashAddrPrefix = network.CashAddrPrefix
-	encodedAddress = cashaddr.CheckEncodeCashAddress(decodedAddress.Hash, decodedAddress.CashAddrPrefix, decodedAddress.Type)
-	return encodedAddress, nil
+	if decodedAddress.TokenAware {
+		return cashaddr.CheckEncodeTokenAwareCashAddress(decodedAddress.Hash, decodedAddress.CashAddrPrefix, decodedAddress.Type)
+	}
+	return cashaddr.CheckEncodeCashAddress(decodedAddress.Hash, decodedAddress.CashAddrPrefix, decodedAddress.Type)
+}
+
+// ToTokenAwareCashAddr converts a cashaddr (or legacy) address into the
+// CashTokens token-aware cashaddr for the same hash
+func (network Network) ToTokenAwareCashAddr(encodedAddress string) (string, error) {
+	return network.convertTokenAwareness(encodedAddress, true)
+}
+
+// ToPlainCashAddr converts a token-aware cashaddr into the plain cashaddr
+// for the same hash, which wallets without CashTokens support understand
+func (network Network) ToPlainCashAddr(encodedAddress string) (string, error) {
+	return network.convertTokenAwareness(encodedAddress, false)
+}
+
+func (network Network) convertTokenAwareness(encodedAddress string, tokenAware bool) (string, error) {
+	decodedAddress, err := network.Decode(encodedAddress)
+	if err != nil {
+		return "", err
+	}
+
+	decodedAddress.TokenAware = tokenAware
+	return network.EncodeToCashAddr(decodedAddress)
 }
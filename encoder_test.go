@@ -74,3 +74,66 @@ func TestEncodeToCashAddr(t *testing.T) {
 		t.Errorf("Incorrect address. Expected %s, got %s", "bitcoincash:pp9w7eldv8fer4hnmxgratvj8pkpalyey5qym9j8x5", encodedAddress)
 	}
 }
+
+func TestP2SH32CashAddr(t *testing.T) {
+	hash, _ := hex.DecodeString("3173ef6623c6b48ffd1a3dcc0cc6489b0a07bb47a37f47cfef4fe69de825c060")
+	script := append(append([]byte{0xaa, 0x20}, hash...), 0x87)
+
+	encodedAddress, err := BitcoinCashNetwork.Encode(script)
+	if err != nil {
+		t.Errorf("Error encoding address: %s", err)
+	}
+
+	decodedAddress, err := BitcoinCashNetwork.Decode(encodedAddress)
+	if err != nil {
+		t.Errorf("Error decoding address: %s", err)
+	}
+
+	if decodedAddress.Type != address.P2SH32 {
+		t.Errorf("Incorrect address type. Expected %d, got %d", address.P2SH32, decodedAddress.Type)
+	}
+
+	if hex.EncodeToString(decodedAddress.Hash) != hex.EncodeToString(hash) {
+		t.Errorf("Incorrect hash. Expected %x, got %x", hash, decodedAddress.Hash)
+	}
+
+	reencodedAddress, err := BitcoinCashNetwork.EncodeToCashAddr(decodedAddress)
+	if err != nil {
+		t.Errorf("Error encoding address: %s", err)
+	}
+
+	if reencodedAddress != encodedAddress {
+		t.Errorf("Incorrect address. Expected %s, got %s", encodedAddress, reencodedAddress)
+	}
+
+	if _, err = BitcoinCashNetwork.EncodeToBase58(decodedAddress); err == nil {
+		t.Errorf("Expected error encoding P2SH32 address to base58")
+	}
+}
+
+func TestTokenAwareConversion(t *testing.T) {
+	plainAddress := "bitcoincash:qr7fzmep8g7h7ymfxy74lgc0v950j3r2959lhtxxsl"
+	tokenAddress := "bitcoincash:zr7fzmep8g7h7ymfxy74lgc0v950j3r295z4y4gq0v"
+
+	encodedAddress, err := BitcoinCashNetwork.ToTokenAwareCashAddr(plainAddress)
+	if err != nil {
+		t.Errorf("Error converting address: %s", err)
+	}
+
+	if encodedAddress != tokenAddress {
+		t.Errorf("Incorrect address. Expected %s, got %s", tokenAddress, encodedAddress)
+	}
+
+	encodedAddress, err = BitcoinCashNetwork.ToPlainCashAddr(tokenAddress)
+	if err != nil {
+		t.Errorf("Error converting address: %s", err)
+	}
+
+	if encodedAddress != plainAddress {
+		t.Errorf("Incorrect address. Expected %s, got %s", plainAddress, encodedAddress)
+	}
+
+	if _, err = BitcoinNetwork.ToTokenAwareCashAddr("1JJ2o6iKB4UXVMHXBSzVvbAKim5su2VUfa"); err == nil {
+		t.Errorf("Expected error converting address on a network without cashaddr")
+	}
+}
@@ -0,0 +1,107 @@
+package addrconv
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/coinhako/addrconv/address"
+)
+
+func TestDecodeErrors(t *testing.T) {
+	var networks = []Network{BitcoinNetwork, BitcoinNetwork, BitcoinNetwork, BitcoinNetwork, BitcoinCashNetwork, BitcoinCashNetwork, BitcoinCashNetwork, LitecoinNetwork}
+	var addresses = []string{
+		"1JJ2o6iKB4UXVMHXBSzVvbAKim5su2VUfb",
+		"1JJ2o6iKB4UXVMHXBSzVvbAKim5su2VU0a",
+		"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t5",
+		"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8fbt4",
+		"qr7fzmep8g7h7ymfxy74lgc0v950j3r2959lhtxxsm",
+		"qr7fzmep8g7h7ymfxy74lgc0v950j3r2959lhtxxsb",
+		"bitcoincash:qr7fzmep8g7h7ymfxy74lgc0v950j3r2959lhtxxsb",
+		"1JJ2o6iKB4UXVMHXBSzVvbAKim5su2VUfa",
+	}
+	var encodings = []address.Encoding{address.Base58, address.Base58, address.Bech32, address.Bech32, address.CashAddr, address.CashAddr, address.CashAddr, address.Base58}
+	var reasons = []error{address.ErrInvalidChecksum, address.ErrInvalidCharacter, address.ErrInvalidChecksum, address.ErrInvalidCharacter, address.ErrInvalidChecksum, address.ErrInvalidCharacter, address.ErrInvalidCharacter, address.ErrInvalidVersion}
+	var positions = []int{-1, 32, -1, 39, -1, 41, 53, -1}
+
+	for i, v := range addresses {
+		_, err := networks[i].Decode(v)
+		if !errors.Is(err, reasons[i]) {
+			t.Errorf("Incorrect error for %s. Expected %s, got %v", v, reasons[i], err)
+			continue
+		}
+
+		var addrErr *address.Error
+		if !errors.As(err, &addrErr) {
+			t.Errorf("Expected *address.Error for %s, got %T", v, err)
+			continue
+		}
+
+		if addrErr.Encoding != encodings[i] {
+			t.Errorf("Incorrect encoding for %s. Expected %s, got %s", v, encodings[i], addrErr.Encoding)
+		}
+
+		if addrErr.Position != positions[i] {
+			t.Errorf("Incorrect position for %s. Expected %d, got %d", v, positions[i], addrErr.Position)
+		}
+	}
+}
+
+func TestEncodeErrors(t *testing.T) {
+	var decodedAddress address.Address
+	decodedAddress.Type = address.P2WPKH
+	decodedAddress.Hash = make([]byte, 20)
+
+	_, err := BitcoinNetwork.EncodeToBase58(decodedAddress)
+	if !errors.Is(err, address.ErrUnsupportedType) {
+		t.Errorf("Incorrect error. Expected %s, got %v", address.ErrUnsupportedType, err)
+	}
+
+	_, err = BitcoinNetwork.EncodeToCashAddr(decodedAddress)
+	if !errors.Is(err, address.ErrUnsupportedEncoding) {
+		t.Errorf("Incorrect error. Expected %s, got %v", address.ErrUnsupportedEncoding, err)
+	}
+
+	_, err = BitcoinCashNetwork.EncodeToCashAddr(decodedAddress)
+	if !errors.Is(err, address.ErrUnsupportedType) {
+		t.Errorf("Incorrect error. Expected %s, got %v", address.ErrUnsupportedType, err)
+	}
+
+	_, err = DogecoinNetwork.Encode([]byte{0x00, 0x14, 0x75, 0x1e, 0x76, 0xe8, 0x19, 0x91, 0x96, 0xd4, 0x54, 0x94, 0x1c, 0x45, 0xd1, 0xb3, 0xa3, 0x23, 0xf1, 0x43, 0x3b, 0xd6})
+	if !errors.Is(err, address.ErrUnsupportedEncoding) {
+		t.Errorf("Incorrect error. Expected %s, got %v", address.ErrUnsupportedEncoding, err)
+	}
+}
+
+func TestDecodeErrorPositions(t *testing.T) {
+	var addresses = []string{
+		"bc1qw508d6qejqtdg4y5r3zarvary0c5xw7kv8f3t5",
+		"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz8vqzk5jj0",
+	}
+	var positions = [][]int{{13, 41}, {53}}
+	var encodings = []address.Encoding{address.Bech32, address.Bech32m}
+
+	for i, v := range addresses {
+		_, err := BitcoinNetwork.Decode(v)
+
+		var addrErr *address.Error
+		if !errors.As(err, &addrErr) || !errors.Is(err, address.ErrInvalidChecksum) {
+			t.Errorf("Expected checksum error for %s, got %v", v, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(addrErr.ErrorPositions, positions[i]) {
+			t.Errorf("Incorrect error positions for %s. Expected %v, got %v", v, positions[i], addrErr.ErrorPositions)
+		}
+
+		if addrErr.Encoding != encodings[i] {
+			t.Errorf("Incorrect encoding for %s. Expected %s, got %s", v, encodings[i], addrErr.Encoding)
+		}
+
+		// Networks the address isn't meant for don't search for errors
+		_, err = LitecoinNetwork.decodeSegwit(v)
+		if !errors.As(err, &addrErr) || addrErr.ErrorPositions != nil {
+			t.Errorf("Expected no error positions for %s on litecoin, got %v", v, err)
+		}
+	}
+}
@@ -1,6 +1,6 @@
 module github.com/coinhako/addrconv
 
-go 1.12
+go 1.13
 
 require (
 	github.com/coinhako/blockutils v0.0.0-20190726112154-ec422ef3a108
@@ -0,0 +1,45 @@
+package addrconv
+
+import (
+	"github.com/coinhako/addrconv/address"
+)
+
+// NetworkMatch is a network that an address string is valid for,
+// along with the address as decoded by that network
+type NetworkMatch struct {
+	Network   Network
+	Address   address.Address
+	Ambiguous bool // the address is also valid for another network
+}
+
+// IdentifyNetworks decodes an address string without any coin context
+// against every network in the default registry, and returns each network it
+// is valid for. Networks that share version bytes (e.g. BTC and BCH legacy addresses,
+// or DGB and DOGE P2PKH addresses) can't be told apart, so when more than
+// one network matches every match is flagged as ambiguous.
+func IdentifyNetworks(encodedAddress string) ([]NetworkMatch, error) {
+	var matches []NetworkMatch
+	for _, network := range defaultRegistry.Networks() {
+		decodedAddress, err := network.Decode(encodedAddress)
+		if err != nil {
+			continue
+		}
+
+		matches = append(matches, NetworkMatch{
+			Network: network,
+			Address: decodedAddress,
+		})
+	}
+
+	if len(matches) == 0 {
+		return matches, address.NewError(address.NoEncoding, address.ErrUnknownAddressType)
+	}
+
+	if len(matches) > 1 {
+		for i := range matches {
+			matches[i].Ambiguous = true
+		}
+	}
+
+	return matches, nil
+}
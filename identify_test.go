@@ -0,0 +1,51 @@
+package addrconv
+
+import (
+	"testing"
+)
+
+func TestIdentifyNetworks(t *testing.T) {
+	var addresses = []string{
+		"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4",
+		"bitcoincash:qr7fzmep8g7h7ymfxy74lgc0v950j3r2959lhtxxsl",
+		"qr7fzmep8g7h7ymfxy74lgc0v950j3r2959lhtxxsl",
+		"1JJ2o6iKB4UXVMHXBSzVvbAKim5su2VUfa",
+		"DCXiSSQwi7gw9YXrMY4mxt2i4hQZEBb5Yv",
+		"aHKKiDdEAYQjjbEgJMSUpdkapz4hVUUCHR",
+	}
+	var networks = [][]string{
+		{"bitcoin"},
+		{"bitcoincash"},
+		{"bitcoincash"},
+		{"bitcoin", "bitcoincash", "ecash"},
+		{"dogecoin", "digibyte"},
+		{"zcoin"},
+	}
+
+	for i, v := range addresses {
+		matches, err := IdentifyNetworks(v)
+		if err != nil {
+			t.Errorf("Error identifying address %s: %s", v, err)
+			continue
+		}
+
+		if len(matches) != len(networks[i]) {
+			t.Errorf("Incorrect number of networks for %s. Expected %d, got %d", v, len(networks[i]), len(matches))
+			continue
+		}
+
+		for j, match := range matches {
+			if match.Network.Name != networks[i][j] {
+				t.Errorf("Incorrect network for %s. Expected %s, got %s", v, networks[i][j], match.Network.Name)
+			}
+
+			if match.Ambiguous != (len(networks[i]) > 1) {
+				t.Errorf("Incorrect ambiguous flag for %s on %s", v, match.Network.Name)
+			}
+		}
+	}
+
+	if _, err := IdentifyNetworks("notanaddress"); err == nil {
+		t.Errorf("Expected error identifying an invalid address")
+	}
+}
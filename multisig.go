@@ -0,0 +1,180 @@
+package addrconv
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"sort"
+
+	"github.com/coinhako/addrconv/address"
+	"github.com/coinhako/addrconv/secp256k1"
+	"github.com/coinhako/blockutils"
+)
+
+// P2SH redeem scripts can't be pushed if they are larger than this
+const maxRedeemScriptSize = 520
+
+// Multisig is an m-of-n multisig script and the addresses that pay to it
+type Multisig struct {
+	Threshold int
+	PubKeys   [][]byte          // in script order
+	Script    blockutils.Script // redeem script for P2SH, witness script for P2WSH
+	Addresses []DerivedAddress
+}
+
+// MultisigScript builds an OP_m <pubkeys> OP_n OP_CHECKMULTISIG script.
+// With sortKeys the keys are sorted as in BIP67 first.
+func MultisigScript(threshold int, pubKeys [][]byte, sortKeys bool) (blockutils.Script, error) {
+	if len(pubKeys) < 1 || len(pubKeys) > 16 || threshold < 1 || threshold > len(pubKeys) {
+		return nil, address.NewError(address.NoEncoding, address.ErrInvalidScript)
+	}
+
+	for _, pubKey := range pubKeys {
+		if _, err := secp256k1.ParsePublicKey(pubKey); err != nil || len(pubKey) == 32 {
+			return nil, secp256k1.ErrInvalidPublicKey
+		}
+	}
+
+	if sortKeys {
+		pubKeys = sortPubKeys(pubKeys)
+	}
+
+	return buildMultisigScript(threshold, pubKeys), nil
+}
+
+func buildMultisigScript(threshold int, pubKeys [][]byte) blockutils.Script {
+	script := []byte{byte(op1 - 1 + threshold)}
+	for _, pubKey := range pubKeys {
+		script = append(script, byte(len(pubKey)))
+		script = append(script, pubKey...)
+	}
+	return append(script, byte(op1-1+len(pubKeys)), opCheckMultisig)
+}
+
+// parseMultisigScript splits a bare OP_m <pubkeys> OP_n OP_CHECKMULTISIG
+// output into its threshold and keys. Like P2PK, keys are only checked
+// for a valid prefix, since protocols such as Counterparty store data in
+// keys that aren't on the curve.
+func parseMultisigScript(script blockutils.Script) (threshold int, pubKeys [][]byte, ok bool) {
+	if len(script) < 3 || script[len(script)-1] != opCheckMultisig {
+		return 0, nil, false
+	}
+
+	threshold = int(script[0]) - (op1 - 1)
+	n := int(script[len(script)-2]) - (op1 - 1)
+	if threshold < 1 || n > 16 || threshold > n {
+		return 0, nil, false
+	}
+
+	for rest := script[1 : len(script)-2]; len(rest) > 0; {
+		pushLength := int(rest[0])
+		if len(rest) < pushLength+1 {
+			return 0, nil, false
+		}
+
+		pubKey := rest[1 : pushLength+1]
+		switch {
+		case pushLength == 33 && (pubKey[0] == 0x02 || pubKey[0] == 0x03):
+		case pushLength == 65 && pubKey[0] == 0x04:
+		default:
+			return 0, nil, false
+		}
+
+		pubKeys = append(pubKeys, pubKey)
+		rest = rest[pushLength+1:]
+	}
+
+	if len(pubKeys) != n {
+		return 0, nil, false
+	}
+
+	return threshold, pubKeys, true
+}
+
+// PubKeyAddresses returns the P2PKH address of each key in a P2PK or
+// bare multisig (P2MS) address, which are the addresses that can spend
+// the output
+func (network Network) PubKeyAddresses(decodedAddress address.Address) ([]DerivedAddress, error) {
+	if decodedAddress.Type != address.P2PK && decodedAddress.Type != address.P2MS {
+		return nil, address.NewError(address.NoEncoding, address.ErrUnsupportedType)
+	}
+
+	addresses := make([]address.Address, len(decodedAddress.PubKeys))
+	for i, pubKey := range decodedAddress.PubKeys {
+		addresses[i] = address.Address{
+			Type:    address.P2PKH,
+			Hash:    blockutils.Hash160(pubKey),
+			Version: network.PubKeyPrefix,
+		}
+	}
+
+	return network.deriveAddresses(addresses)
+}
+
+// sortPubKeys returns a copy of the keys in BIP67 order
+func sortPubKeys(pubKeys [][]byte) [][]byte {
+	sorted := make([][]byte, len(pubKeys))
+	copy(sorted, pubKeys)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i], sorted[j]) < 0
+	})
+	return sorted
+}
+
+// MultisigAddresses builds an m-of-n multisig script and returns its
+// P2SH address, and its P2WSH and P2SH-P2WSH addresses on networks with
+// segwit. Segwit addresses are left out if any key is uncompressed,
+// since those are non-standard in witness scripts. On cashaddr networks
+// the P2SH address also carries its cashaddr encoding.
+func (network Network) MultisigAddresses(threshold int, pubKeys [][]byte, sortKeys bool) (multisig Multisig, err error) {
+	script, err := MultisigScript(threshold, pubKeys, sortKeys)
+	if err != nil {
+		return multisig, err
+	}
+
+	if sortKeys {
+		pubKeys = sortPubKeys(pubKeys)
+	}
+
+	compressed := true
+	for _, pubKey := range pubKeys {
+		compressed = compressed && len(pubKey) == 33
+	}
+
+	var addresses []address.Address
+	if len(script) <= maxRedeemScriptSize {
+		addresses = append(addresses, address.Address{
+			Type:    address.P2SH,
+			Hash:    blockutils.Hash160(script),
+			Version: network.ScriptHashPrefix,
+		})
+	}
+
+	if network.SupportsBech32() && compressed {
+		scriptHash := sha256.Sum256(script)
+		witnessScript := append([]byte{op0, 32}, scriptHash[:]...)
+		addresses = append(addresses, address.Address{
+			Type:    address.P2SH_P2WSH,
+			Hash:    blockutils.Hash160(witnessScript),
+			Version: network.ScriptHashPrefix,
+		}, address.Address{
+			Type:      address.P2WSH,
+			Hash:      scriptHash[:],
+			Bech32HRP: network.Bech32Prefix,
+		})
+	}
+
+	if len(addresses) == 0 {
+		return multisig, address.NewError(address.NoEncoding, address.ErrInvalidScript)
+	}
+
+	multisig.Addresses, err = network.deriveAddresses(addresses)
+	if err != nil {
+		return multisig, err
+	}
+
+	multisig.Threshold = threshold
+	multisig.PubKeys = pubKeys
+	multisig.Script = script
+
+	return multisig, nil
+}
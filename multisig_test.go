@@ -0,0 +1,133 @@
+package addrconv
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/coinhako/addrconv/address"
+)
+
+func TestMultisigAddresses(t *testing.T) {
+	// BIP67 test vector, keys given in unsorted order
+	var keys = []string{
+		"02ff12471208c14bd580709cb2358d98975247d8765f92bc25eab3b2763ed605f8",
+		"02fe6f0a5a297eb38c391581c4413e084773ea23954d93f7753db7dc0adc188b2f",
+	}
+	var pubKeys [][]byte
+	for _, v := range keys {
+		pubKey, _ := hex.DecodeString(v)
+		pubKeys = append(pubKeys, pubKey)
+	}
+
+	multisig, err := BitcoinNetwork.MultisigAddresses(2, pubKeys, true)
+	if err != nil {
+		t.Fatalf("Error building multisig: %s", err)
+	}
+
+	expectedScript := "522102fe6f0a5a297eb38c391581c4413e084773ea23954d93f7753db7dc0adc188b2f2102ff12471208c14bd580709cb2358d98975247d8765f92bc25eab3b2763ed605f852ae"
+	if multisig.Script.String() != expectedScript {
+		t.Errorf("Incorrect script. Expected %s, got %s", expectedScript, multisig.Script)
+	}
+
+	var types = []address.AddressType{address.P2SH, address.P2SH_P2WSH, address.P2WSH}
+	if len(multisig.Addresses) != len(types) {
+		t.Fatalf("Incorrect number of addresses. Expected %d, got %d", len(types), len(multisig.Addresses))
+	}
+	for i, d := range multisig.Addresses {
+		if d.Address.Type != types[i] {
+			t.Errorf("Incorrect address type. Expected %d, got %d", types[i], d.Address.Type)
+		}
+	}
+	if multisig.Addresses[0].Encoded != "39bgKC7RFbpoCRbtD5KEdkYKtNyhpsNa3Z" {
+		t.Errorf("Incorrect P2SH address. Expected %s, got %s", "39bgKC7RFbpoCRbtD5KEdkYKtNyhpsNa3Z", multisig.Addresses[0].Encoded)
+	}
+
+	// The P2WSH program is the sha256 of the witness script
+	script, err := BitcoinNetwork.DecodeToScript(multisig.Addresses[2].Encoded)
+	if err != nil {
+		t.Errorf("Error decoding %s: %s", multisig.Addresses[2].Encoded, err)
+	}
+	expectedProgram := "0020" + hex.EncodeToString(sha256Sum(multisig.Script))
+	if script.String() != expectedProgram {
+		t.Errorf("Incorrect P2WSH script. Expected %s, got %s", expectedProgram, script)
+	}
+
+	// Unsorted keys keep their order
+	unsorted, err := BitcoinNetwork.MultisigAddresses(1, pubKeys, false)
+	if err != nil || hex.EncodeToString(unsorted.Script[2:35]) != keys[0] || unsorted.Script[0] != 0x51 {
+		t.Errorf("Incorrect unsorted script %s: %v", unsorted.Script, err)
+	}
+
+	// Bitcoin Cash has no segwit, but gets a cashaddr
+	bch, err := BitcoinCashNetwork.MultisigAddresses(2, pubKeys, true)
+	if err != nil || len(bch.Addresses) != 1 || bch.Addresses[0].CashAddr == "" {
+		t.Errorf("Expected a single cashaddr P2SH address on bitcoin cash, got %v / %v", bch.Addresses, err)
+	}
+
+	for _, threshold := range []int{0, 3} {
+		if _, err := BitcoinNetwork.MultisigAddresses(threshold, pubKeys, true); err == nil {
+			t.Errorf("Expected error for a %d-of-2 multisig", threshold)
+		}
+	}
+	if _, err := BitcoinNetwork.MultisigAddresses(1, [][]byte{pubKeys[0][:32]}, true); err == nil {
+		t.Errorf("Expected error for an x-only key")
+	}
+}
+
+func sha256Sum(data []byte) []byte {
+	sum := sha256.Sum256(data)
+	return sum[:]
+}
+
+func TestBareMultisig(t *testing.T) {
+	// 1-of-2 with the compressed and uncompressed forms of the generator
+	compressedKey := "0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798"
+	uncompressedKey := "0479be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8"
+	v := "5121" + compressedKey + "41" + uncompressedKey + "52ae"
+	script, _ := hex.DecodeString(v)
+
+	decodedAddress, err := BitcoinNetwork.FromScript(script)
+	if err != nil {
+		t.Fatalf("Error classifying script: %s", err)
+	}
+
+	if decodedAddress.Type != address.P2MS || decodedAddress.Threshold != 1 || len(decodedAddress.PubKeys) != 2 {
+		t.Errorf("Incorrect bare multisig. Expected 1-of-2 P2MS, got %d %d-of-%d", decodedAddress.Type, decodedAddress.Threshold, len(decodedAddress.PubKeys))
+	}
+
+	derived, err := BitcoinNetwork.PubKeyAddresses(decodedAddress)
+	if err != nil {
+		t.Fatalf("Error deriving addresses: %s", err)
+	}
+	var addresses = []string{"1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH", "1EHNa6Q4Jz2uvNExL497mE43ikXhwF6kZm"}
+	for i, d := range derived {
+		if d.Encoded != addresses[i] {
+			t.Errorf("Incorrect address. Expected %s, got %s", addresses[i], d.Encoded)
+		}
+	}
+
+	roundTrip, err := BitcoinNetwork.ToScript(decodedAddress)
+	if err != nil || roundTrip.String() != v {
+		t.Errorf("Incorrect script. Expected %s, got %s / %v", v, roundTrip, err)
+	}
+
+	// Encode still gives the raw script
+	if encoded, _ := BitcoinNetwork.Encode(script); encoded != v {
+		t.Errorf("Incorrect encoding. Expected %s, got %s", v, encoded)
+	}
+
+	var invalidScripts = []string{
+		"5221" + compressedKey + "51ae",        // m > n
+		"5121" + compressedKey + "52ae",        // fewer keys than n
+		"5120" + compressedKey[2:] + "51ae",    // not a key
+		"5121" + compressedKey[:64] + "51ae",   // truncated push
+		"5121" + compressedKey + "41" + "51ae", // push past the end
+	}
+	for _, v := range invalidScripts {
+		script, _ := hex.DecodeString(v)
+		if decodedAddress, err := BitcoinNetwork.FromScript(script); err == nil {
+			t.Errorf("Expected error for %s, got type %d", v, decodedAddress.Type)
+		}
+	}
+}
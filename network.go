@@ -1,135 +1,220 @@
 package addrconv
 
-import (
-	"strings"
-)
-
 type Network struct {
-	Bech32Prefix     string // Human readable part of bech32 addresses
-	PubKeyPrefix     byte   // P2PKH address prefix
-	ScriptHashPrefix byte   // P2SH address prefix
-	WIFPrefix        byte   // wif key prefix
-	BIP32PubPrefix   []byte // extended public key prefix
-	BIP32PrivPrefix  []byte // extended private key prefix
-	CashAddrPrefix   string //cashaddr prefix
+	Name             string   // coin name, as accepted by GetNetwork
+	Ticker           string   // coin ticker, as accepted by GetNetworkByTicker
+	Aliases          []string // other names or tickers the coin is known by
+	CoinType         uint32   // SLIP-44 coin type, only used if HasCoinType is set
+	HasCoinType      bool     // set for networks with a SLIP-44 coin type, since 0 is bitcoin's
+	Bech32Prefix     string   // Human readable part of bech32 addresses
+	PubKeyPrefix     byte     // P2PKH address prefix
+	ScriptHashPrefix byte     // P2SH address prefix
+	WIFPrefix        byte     // wif key prefix
+	BIP32PubPrefix   []byte   // extended public key prefix
+	BIP32PrivPrefix  []byte   // extended private key prefix
+	CashAddrPrefix   string   //cashaddr prefix
+
+	// Deprecated P2SH address prefixes, still accepted by Decode
+	LegacyScriptHashPrefixes []byte
+
+	// Other cashaddr prefixes for the same chain, such as SLP token
+	// addresses, accepted by Decode but never used for encoding
+	CashAddrPrefixAliases []string
+
+	// Largest OP_RETURN output script treated as standard, zero means
+	// 83 bytes, the default of Bitcoin Core before v30
+	MaxNullDataSize int
 }
 
 var BitcoinNetwork = Network{
+	Name:             "bitcoin",
+	Ticker:           "btc",
+	CoinType:         0,
+	HasCoinType:      true,
 	Bech32Prefix:     "bc",
 	PubKeyPrefix:     0x00,
 	ScriptHashPrefix: 0x05,
 	WIFPrefix:        0x80,
 	BIP32PubPrefix:   []byte{0x04, 0x88, 0xb2, 0x1e},
 	BIP32PrivPrefix:  []byte{0x04, 0x88, 0xad, 0xe4},
+	MaxNullDataSize:  83,
 }
 
 var BitcoinCashNetwork = Network{
+	Name:             "bitcoincash",
+	Ticker:           "bch",
+	CoinType:         145,
+	HasCoinType:      true,
 	PubKeyPrefix:     0x00,
 	ScriptHashPrefix: 0x05,
 	WIFPrefix:        0x80,
 	BIP32PubPrefix:   []byte{0x04, 0x88, 0xb2, 0x1e},
 	BIP32PrivPrefix:  []byte{0x04, 0x88, 0xad, 0xe4},
 	CashAddrPrefix:   "bitcoincash",
+	MaxNullDataSize:  223,
+
+	CashAddrPrefixAliases: []string{"simpleledger"},
+}
+
+var BitcoinCashTestnetNetwork = Network{
+	Name:             "bitcoincash-testnet",
+	Ticker:           "tbch",
+	CoinType:         1,
+	HasCoinType:      true,
+	PubKeyPrefix:     0x6f,
+	ScriptHashPrefix: 0xc4,
+	WIFPrefix:        0xef,
+	BIP32PubPrefix:   []byte{0x04, 0x35, 0x87, 0xcf},
+	BIP32PrivPrefix:  []byte{0x04, 0x35, 0x83, 0x94},
+	CashAddrPrefix:   "bchtest",
+	MaxNullDataSize:  223,
+
+	CashAddrPrefixAliases: []string{"slptest"},
+}
+
+var BitcoinCashRegtestNetwork = Network{
+	Name:             "bitcoincash-regtest",
+	Ticker:           "rbch",
+	CoinType:         1,
+	HasCoinType:      true,
+	PubKeyPrefix:     0x6f,
+	ScriptHashPrefix: 0xc4,
+	WIFPrefix:        0xef,
+	BIP32PubPrefix:   []byte{0x04, 0x35, 0x87, 0xcf},
+	BIP32PrivPrefix:  []byte{0x04, 0x35, 0x83, 0x94},
+	CashAddrPrefix:   "bchreg",
+	MaxNullDataSize:  223,
+}
+
+var ECashNetwork = Network{
+	Name:             "ecash",
+	Ticker:           "xec",
+	CoinType:         899,
+	HasCoinType:      true,
+	PubKeyPrefix:     0x00,
+	ScriptHashPrefix: 0x05,
+	WIFPrefix:        0x80,
+	BIP32PubPrefix:   []byte{0x04, 0x88, 0xb2, 0x1e},
+	BIP32PrivPrefix:  []byte{0x04, 0x88, 0xad, 0xe4},
+	CashAddrPrefix:   "ecash",
+	MaxNullDataSize:  223,
+}
+
+var ECashTestnetNetwork = Network{
+	Name:             "ecash-testnet",
+	Ticker:           "txec",
+	CoinType:         1,
+	HasCoinType:      true,
+	PubKeyPrefix:     0x6f,
+	ScriptHashPrefix: 0xc4,
+	WIFPrefix:        0xef,
+	BIP32PubPrefix:   []byte{0x04, 0x35, 0x87, 0xcf},
+	BIP32PrivPrefix:  []byte{0x04, 0x35, 0x83, 0x94},
+	CashAddrPrefix:   "ectest",
+	MaxNullDataSize:  223,
 }
 
 var DigibyteNetwork = Network{
+	Name:             "digibyte",
+	Ticker:           "dgb",
+	CoinType:         20,
+	HasCoinType:      true,
 	Bech32Prefix:     "dgb",
 	PubKeyPrefix:     0x1e,
 	ScriptHashPrefix: 0x3f,
 	WIFPrefix:        0x9e,
 	BIP32PubPrefix:   []byte{0x04, 0x88, 0xb2, 0x1e},
 	BIP32PrivPrefix:  []byte{0x04, 0x88, 0xad, 0xe4},
+	MaxNullDataSize:  83,
+
+	LegacyScriptHashPrefixes: []byte{0x05},
 }
 
 var LitecoinNetwork = Network{
+	Name:             "litecoin",
+	Ticker:           "ltc",
+	CoinType:         2,
+	HasCoinType:      true,
 	Bech32Prefix:     "ltc",
 	PubKeyPrefix:     0x30,
 	ScriptHashPrefix: 0x32,
 	WIFPrefix:        0xb0,
 	BIP32PubPrefix:   []byte{0x04, 0x88, 0xb2, 0x1e},
 	BIP32PrivPrefix:  []byte{0x04, 0x88, 0xad, 0xe4},
+	MaxNullDataSize:  83,
+
+	LegacyScriptHashPrefixes: []byte{0x05},
 }
 
 var ZcoinNetwork = Network{
+	Name:             "zcoin",
+	Ticker:           "xzc",
+	Aliases:          []string{"firo"},
+	CoinType:         136,
+	HasCoinType:      true,
 	PubKeyPrefix:     0x52,
 	ScriptHashPrefix: 0x07,
 	WIFPrefix:        0xd2,
 	BIP32PubPrefix:   []byte{0x04, 0x88, 0xb2, 0x1e},
 	BIP32PrivPrefix:  []byte{0x04, 0x88, 0xad, 0xe4},
+	MaxNullDataSize:  83,
 }
 
 var DogecoinNetwork = Network{
+	Name:             "dogecoin",
+	Ticker:           "doge",
+	CoinType:         3,
+	HasCoinType:      true,
 	PubKeyPrefix:     0x1e,
 	ScriptHashPrefix: 0x16,
 	WIFPrefix:        0x9e,
 	BIP32PubPrefix:   []byte{0x02, 0xfa, 0xca, 0xfd},
 	BIP32PrivPrefix:  []byte{0x02, 0xfa, 0xc3, 0x98},
+	MaxNullDataSize:  83,
 }
 
-// Returns the predefined network settings for common coins
-// based on the provided coin name
-func GetNetwork(name string) Network {
-	name = strings.ToLower(name)
-	if name == "bitcoin" {
-		return BitcoinNetwork
-	}
-
-	if name == "digibyte" {
-		return DigibyteNetwork
-	}
-
-	if name == "litecoin" {
-		return LitecoinNetwork
-	}
-
-	if name == "bitcoincash" {
-		return BitcoinCashNetwork
-	}
-
-	if name == "zcoin" {
-		return ZcoinNetwork
-	}
-
-	if name == "dogecoin" {
-		return DogecoinNetwork
-	}
-
-	return BitcoinNetwork
+// knownNetworks lists the predefined networks, in the order they are
+// registered in the default registry
+var knownNetworks = []Network{
+	BitcoinNetwork,
+	BitcoinCashNetwork,
+	LitecoinNetwork,
+	DogecoinNetwork,
+	DigibyteNetwork,
+	ZcoinNetwork,
+	ECashNetwork,
+	BitcoinCashTestnetNetwork,
+	BitcoinCashRegtestNetwork,
+	ECashTestnetNetwork,
 }
 
-func GetNetworkByTicker(ticker string) Network {
-	name := strings.ToLower(ticker)
-	if name == "btc" {
-		return BitcoinNetwork
-	}
-
-	if name == "dgb" {
-		return DigibyteNetwork
-	}
-
-	if name == "ltc" {
-		return LitecoinNetwork
-	}
-
-	if name == "bch" {
-		return BitcoinCashNetwork
-	}
-
-	if name == "xzc" {
-		return ZcoinNetwork
-	}
-
-	if name == "doge" {
-		return DogecoinNetwork
-	}
-
-	return BitcoinNetwork
+// KnownNetworks returns the predefined networks, in the order they are
+// registered in the default registry
+func KnownNetworks() []Network {
+	networks := make([]Network, len(knownNetworks))
+	copy(networks, knownNetworks)
+	return networks
 }
 
 func (network Network) SupportsCashAddr() bool {
 	return network.CashAddrPrefix != ""
 }
 
+// cashAddrPrefixes returns every cashaddr prefix the network accepts,
+// starting with the one it encodes with
+func (network Network) cashAddrPrefixes() []string {
+	return append([]string{network.CashAddrPrefix}, network.CashAddrPrefixAliases...)
+}
+
+func (network Network) isLegacyScriptHashPrefix(version byte) bool {
+	for _, prefix := range network.LegacyScriptHashPrefixes {
+		if version == prefix {
+			return true
+		}
+	}
+	return false
+}
+
 func (network Network) SupportsBech32() bool {
 	return network.Bech32Prefix != ""
 }
@@ -0,0 +1,148 @@
+// Package nulldata parses OP_RETURN (null data) output scripts into the
+// chunks of data they push, and recognises well known protocols by their
+// markers.
+package nulldata
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+)
+
+// Errors returned when parsing a null data script
+var (
+	ErrNotNullData = errors.New("nulldata: script doesn't start with OP_RETURN")
+	ErrInvalidPush = errors.New("nulldata: invalid or truncated push")
+)
+
+const (
+	opReturn    = 0x6a
+	opPushData1 = 0x4c
+	opPushData2 = 0x4d
+	opPushData4 = 0x4e
+	op1Negate   = 0x4f
+	op1         = 0x51
+	op13        = 0x5d
+	op16        = 0x60
+)
+
+// Protocol is a protocol recognised from the marker of a null data output
+type Protocol int
+
+const (
+	Unknown Protocol = 0
+	Omni    Protocol = 1 // first push starts with "omni"
+
+	// HashCommitment is a single 32 byte push of nothing but a hash. This
+	// is how OpenTimestamps calendars commit to their merkle tips, but
+	// other timestamping services write the same thing, so the protocol
+	// behind a commitment can't be told from the output alone.
+	HashCommitment Protocol = 2
+	Runes          Protocol = 3 // OP_RETURN OP_13 runestone
+	SLP            Protocol = 4 // BCH Simple Ledger Protocol, first push is "SLP\0"
+	BCMR           Protocol = 5 // BCH CashTokens metadata registry, first push is "BCMR"
+)
+
+func (protocol Protocol) String() string {
+	switch protocol {
+	case Omni:
+		return "omni"
+	case HashCommitment:
+		return "hash commitment"
+	case Runes:
+		return "runes"
+	case SLP:
+		return "slp"
+	case BCMR:
+		return "bcmr"
+	}
+	return "unknown"
+}
+
+var (
+	omniMarker = []byte("omni")
+	slpMarker  = []byte("SLP\x00")
+	bcmrMarker = []byte("BCMR")
+)
+
+// Payload is the data carried by a null data output
+type Payload struct {
+	Protocol Protocol
+	Chunks   [][]byte // data pushed after OP_RETURN, including any marker push
+}
+
+// Parse splits an OP_RETURN script into the data it pushes. Small integer
+// opcodes are returned as their minimal push, and for runestones the
+// OP_13 tag is left out of the chunks.
+func Parse(script []byte) (*Payload, error) {
+	if len(script) == 0 || script[0] != opReturn {
+		return nil, ErrNotNullData
+	}
+
+	var payload Payload
+	rest := script[1:]
+	if len(rest) > 0 && rest[0] == op13 {
+		payload.Protocol = Runes
+		rest = rest[1:]
+	}
+
+	for len(rest) > 0 {
+		chunk, n, err := readPush(rest)
+		if err != nil {
+			return nil, err
+		}
+		payload.Chunks = append(payload.Chunks, chunk)
+		rest = rest[n:]
+	}
+
+	if payload.Protocol == Unknown && len(payload.Chunks) > 0 {
+		payload.Protocol = detectProtocol(payload.Chunks)
+	}
+
+	return &payload, nil
+}
+
+func detectProtocol(chunks [][]byte) Protocol {
+	switch {
+	case bytes.HasPrefix(chunks[0], omniMarker):
+		return Omni
+	case bytes.Equal(chunks[0], slpMarker):
+		return SLP
+	case bytes.Equal(chunks[0], bcmrMarker):
+		return BCMR
+	case len(chunks) == 1 && len(chunks[0]) == 32:
+		return HashCommitment
+	}
+	return Unknown
+}
+
+// readPush reads a single push opcode and returns the data it pushes and
+// the number of script bytes it used
+func readPush(script []byte) (chunk []byte, n int, err error) {
+	op := script[0]
+	var length, header int
+	switch {
+	case op == 0:
+		return []byte{}, 1, nil
+	case op < opPushData1:
+		length, header = int(op), 1
+	case op == opPushData1 && len(script) >= 2:
+		length, header = int(script[1]), 2
+	case op == opPushData2 && len(script) >= 3:
+		length, header = int(binary.LittleEndian.Uint16(script[1:3])), 3
+	case op == opPushData4 && len(script) >= 5:
+		length, header = int(binary.LittleEndian.Uint32(script[1:5])), 5
+	case op == op1Negate:
+		return []byte{0x81}, 1, nil
+	case op >= op1 && op <= op16:
+		return []byte{op - op1 + 1}, 1, nil
+	default:
+		return nil, 0, ErrInvalidPush
+	}
+
+	if length < 0 || len(script)-header < length {
+		return nil, 0, ErrInvalidPush
+	}
+
+	return script[header : header+length], header + length, nil
+}
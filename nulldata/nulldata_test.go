@@ -0,0 +1,70 @@
+package nulldata
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	var scripts = []string{
+		"6a21e5b88ce69c9be4bda0e698afe4b880e4b8aae69c89e59381e591b3e79a84e4baba",
+		"6a146f6d6e69000000000000001f0000000005f5e100",
+		"6a5d0614c0a2330a01",
+		"6a04534c500001010453454e44",
+		"6a0442434d5220" + strings.Repeat("ab", 32),
+		"6a20" + strings.Repeat("cd", 32),
+		"6a20" + strings.Repeat("cd", 32) + "0101",
+		"6a4c03616263",
+		"6a4d0300616263",
+		"6a0051604f",
+		"6a",
+	}
+	var protocols = []Protocol{Unknown, Omni, Runes, SLP, BCMR, HashCommitment, Unknown, Unknown, Unknown, Unknown, Unknown}
+	var chunks = [][]string{
+		{"e5b88ce69c9be4bda0e698afe4b880e4b8aae69c89e59381e591b3e79a84e4baba"},
+		{"6f6d6e69000000000000001f0000000005f5e100"},
+		{"14c0a2330a01"},
+		{"534c5000", "01", "53454e44"},
+		{"42434d52", strings.Repeat("ab", 32)},
+		{strings.Repeat("cd", 32)},
+		{strings.Repeat("cd", 32), "01"},
+		{"616263"},
+		{"616263"},
+		{"", "01", "10", "81"},
+		{},
+	}
+
+	for i, v := range scripts {
+		script, _ := hex.DecodeString(v)
+		payload, err := Parse(script)
+		if err != nil {
+			t.Errorf("Error parsing %s: %s", v, err)
+			continue
+		}
+
+		if payload.Protocol != protocols[i] {
+			t.Errorf("Incorrect protocol for %s. Expected %s, got %s", v, protocols[i], payload.Protocol)
+		}
+
+		if len(payload.Chunks) != len(chunks[i]) {
+			t.Errorf("Incorrect number of chunks for %s. Expected %d, got %d", v, len(chunks[i]), len(payload.Chunks))
+			continue
+		}
+
+		for j, chunk := range payload.Chunks {
+			if hex.EncodeToString(chunk) != chunks[i][j] {
+				t.Errorf("Incorrect chunk. Expected %s, got %x", chunks[i][j], chunk)
+			}
+		}
+	}
+
+	var invalidScripts = []string{"", "76a9", "6a05aabb", "6aac", "6a4c", "6a4e0500"}
+	var errs = []error{ErrNotNullData, ErrNotNullData, ErrInvalidPush, ErrInvalidPush, ErrInvalidPush, ErrInvalidPush}
+	for i, v := range invalidScripts {
+		script, _ := hex.DecodeString(v)
+		if _, err := Parse(script); err != errs[i] {
+			t.Errorf("Incorrect error for %s. Expected %v, got %v", v, errs[i], err)
+		}
+	}
+}
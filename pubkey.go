@@ -0,0 +1,150 @@
+package addrconv
+
+import (
+	"github.com/coinhako/addrconv/address"
+	"github.com/coinhako/addrconv/secp256k1"
+	"github.com/coinhako/addrconv/taproot"
+	"github.com/coinhako/blockutils"
+)
+
+// DerivedAddress is an address built from keys or scripts, along with
+// its string encoding for the network
+type DerivedAddress struct {
+	Address  address.Address
+	Encoded  string
+	CashAddr string // only set for networks and types that support cashaddr
+}
+
+// PublicKeyAddresses returns every standard address a secp256k1 public
+// key can receive to on the network: P2PKH, P2SH-P2WPKH, P2WPKH and the
+// BIP86 key-path P2TR address.
+//
+// Segwit addresses are only returned for networks with bech32 support,
+// and only for compressed keys, since uncompressed keys are non-standard
+// in segwit outputs. An uncompressed key only has a P2PKH address, and an
+// x-only key only has a P2TR address.
+func (network Network) PublicKeyAddresses(pubKey []byte) ([]DerivedAddress, error) {
+	if _, err := secp256k1.ParsePublicKey(pubKey); err != nil {
+		return nil, err
+	}
+
+	var addresses []address.Address
+	xOnly := len(pubKey) == 32
+	if !xOnly {
+		addresses = append(addresses, address.Address{
+			Type:    address.P2PKH,
+			Hash:    blockutils.Hash160(pubKey),
+			Version: network.PubKeyPrefix,
+		})
+	}
+
+	compressed := len(pubKey) == 33
+	if network.SupportsBech32() && compressed {
+		keyHash := blockutils.Hash160(pubKey)
+		redeemScript := append([]byte{op0, 20}, keyHash...)
+		addresses = append(addresses, address.Address{
+			Type:    address.P2SH_P2WPKH,
+			Hash:    blockutils.Hash160(redeemScript),
+			Version: network.ScriptHashPrefix,
+		}, address.Address{
+			Type:      address.P2WPKH,
+			Hash:      keyHash,
+			Bech32HRP: network.Bech32Prefix,
+		})
+	}
+
+	if network.SupportsBech32() && (compressed || xOnly) {
+		outputKey, err := taproot.OutputKey(pubKey)
+		if err != nil {
+			return nil, err
+		}
+		addresses = append(addresses, address.Address{
+			Type:      address.P2TR,
+			Hash:      outputKey,
+			Bech32HRP: network.Bech32Prefix,
+		})
+	}
+
+	if len(addresses) == 0 {
+		return nil, address.NewError(address.Bech32m, address.ErrUnsupportedEncoding)
+	}
+
+	return network.deriveAddresses(addresses)
+}
+
+// TaprootAddress returns the P2TR address for an internal key tweaked
+// with the root of a script tree, as in BIP341. A nil merkle root gives
+// the key-path only address from BIP86.
+func (network Network) TaprootAddress(internalKey []byte, merkleRoot []byte) (DerivedAddress, error) {
+	if !network.SupportsBech32() {
+		return DerivedAddress{}, address.NewError(address.Bech32m, address.ErrUnsupportedEncoding)
+	}
+
+	outputKey, _, err := taproot.TweakPublicKey(internalKey, merkleRoot)
+	if err != nil {
+		return DerivedAddress{}, err
+	}
+
+	derived, err := network.deriveAddresses([]address.Address{{
+		Type:      address.P2TR,
+		Hash:      outputKey,
+		Bech32HRP: network.Bech32Prefix,
+	}})
+	if err != nil {
+		return DerivedAddress{}, err
+	}
+
+	return derived[0], nil
+}
+
+// TaprootTreeAddress returns the P2TR address committing to an internal
+// key and a tapscript tree, along with the merkle root and the control
+// block needed to spend each leaf
+func (network Network) TaprootTreeAddress(internalKey []byte, tree *taproot.Node) (DerivedAddress, *taproot.Output, error) {
+	if !network.SupportsBech32() {
+		return DerivedAddress{}, nil, address.NewError(address.Bech32m, address.ErrUnsupportedEncoding)
+	}
+
+	output, err := taproot.NewOutput(internalKey, tree)
+	if err != nil {
+		return DerivedAddress{}, nil, err
+	}
+
+	derived, err := network.deriveAddresses([]address.Address{{
+		Type:      address.P2TR,
+		Hash:      output.OutputKey,
+		Bech32HRP: network.Bech32Prefix,
+	}})
+	if err != nil {
+		return DerivedAddress{}, nil, err
+	}
+
+	return derived[0], output, nil
+}
+
+// deriveAddresses encodes each address through its output script, so
+// derived strings always match what Encode produces
+func (network Network) deriveAddresses(addresses []address.Address) ([]DerivedAddress, error) {
+	derived := make([]DerivedAddress, len(addresses))
+	for i, decodedAddress := range addresses {
+		script, err := network.ToScript(decodedAddress)
+		if err != nil {
+			return nil, err
+		}
+
+		encodedAddress, err := network.Encode(script)
+		if err != nil {
+			return nil, err
+		}
+
+		derived[i] = DerivedAddress{Address: decodedAddress, Encoded: encodedAddress}
+		if network.SupportsCashAddr() && (decodedAddress.Type == address.P2PKH || decodedAddress.Type == address.P2SH) {
+			derived[i].CashAddr, err = network.EncodeToCashAddr(decodedAddress)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return derived, nil
+}
@@ -0,0 +1,148 @@
+package addrconv
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/coinhako/addrconv/address"
+	"github.com/coinhako/addrconv/taproot"
+)
+
+func TestPublicKeyAddresses(t *testing.T) {
+	var pubKeys = []string{
+		"0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+		"0479be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8",
+		"cc8a4bc64d897bddc5fbc2f670f7a8ba0b386779106cf1223c6fc5d7cd6fc115",
+	}
+	var types = [][]address.AddressType{
+		{address.P2PKH, address.P2SH_P2WPKH, address.P2WPKH, address.P2TR},
+		{address.P2PKH},
+		{address.P2TR},
+	}
+	var addresses = [][]string{
+		{"1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH", "3JvL6Ymt8MVWiCNHC7oWU6nLeHNJKLZGLN", "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", ""},
+		{"1EHNa6Q4Jz2uvNExL497mE43ikXhwF6kZm"},
+		{"bc1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxqkedrcr"},
+	}
+
+	for i, v := range pubKeys {
+		pubKey, _ := hex.DecodeString(v)
+		derived, err := BitcoinNetwork.PublicKeyAddresses(pubKey)
+		if err != nil {
+			t.Errorf("Error deriving addresses for %s: %s", v, err)
+			continue
+		}
+
+		if len(derived) != len(types[i]) {
+			t.Errorf("Incorrect number of addresses for %s. Expected %d, got %d", v, len(types[i]), len(derived))
+			continue
+		}
+
+		for j, d := range derived {
+			if d.Address.Type != types[i][j] {
+				t.Errorf("Incorrect address type. Expected %d, got %d", types[i][j], d.Address.Type)
+			}
+
+			// The P2TR address of G has no well known vector, just check it decodes
+			if addresses[i][j] == "" {
+				decodedAddress, err := BitcoinNetwork.Decode(d.Encoded)
+				if err != nil || decodedAddress.Type != address.P2TR {
+					t.Errorf("Incorrect P2TR address %s: %v", d.Encoded, err)
+				}
+				continue
+			}
+
+			if d.Encoded != addresses[i][j] {
+				t.Errorf("Incorrect address. Expected %s, got %s", addresses[i][j], d.Encoded)
+			}
+		}
+	}
+
+	pubKey, _ := hex.DecodeString(pubKeys[0])
+	derived, err := DogecoinNetwork.PublicKeyAddresses(pubKey)
+	if err != nil || len(derived) != 1 || derived[0].Address.Type != address.P2PKH {
+		t.Errorf("Expected only a P2PKH address on dogecoin, got %v / %v", derived, err)
+	}
+
+	// Not on the curve
+	pubKey, _ = hex.DecodeString(pubKeys[1])
+	pubKey[64] ^= 0x01
+	if _, err = BitcoinNetwork.PublicKeyAddresses(pubKey); err == nil {
+		t.Errorf("Expected error for an invalid public key")
+	}
+}
+
+func TestTaprootAddress(t *testing.T) {
+	// BIP341 wallet test vectors
+	var internalKeys = []string{
+		"d6889cb081036e0faefa3a35157ad71086b123b2b144b649798b494c300a961d",
+		"187791b6f712a8ea41c8ecdd0ee77fab3e85263b37e1ec18a3651926b3a6cf27",
+	}
+	var merkleRoots = []string{
+		"",
+		"5b75adecf53548f3ec6ad7d78383bf84cc57b55a3127c72b9a2481752dd88b21",
+	}
+	var addresses = []string{
+		"bc1p2wsldez5mud2yam29q22wgfh9439spgduvct83k3pm50fcxa5dps59h4z5",
+		"bc1pz37fc4cn9ah8anwm4xqqhvxygjf9rjf2resrw8h8w4tmvcs0863sa2e586",
+	}
+
+	for i, v := range internalKeys {
+		internalKey, _ := hex.DecodeString(v)
+		var merkleRoot []byte
+		if merkleRoots[i] != "" {
+			merkleRoot, _ = hex.DecodeString(merkleRoots[i])
+		}
+
+		derived, err := BitcoinNetwork.TaprootAddress(internalKey, merkleRoot)
+		if err != nil {
+			t.Errorf("Error deriving taproot address for %s: %s", v, err)
+			continue
+		}
+
+		if derived.Encoded != addresses[i] {
+			t.Errorf("Incorrect address. Expected %s, got %s", addresses[i], derived.Encoded)
+		}
+	}
+
+	internalKey, _ := hex.DecodeString(internalKeys[0])
+	if _, err := DogecoinNetwork.TaprootAddress(internalKey, nil); err == nil {
+		t.Errorf("Expected error for a network without bech32")
+	}
+}
+
+func TestTaprootTreeAddress(t *testing.T) {
+	// BIP341 wallet test vector with a single leaf
+	internalKey, _ := hex.DecodeString("187791b6f712a8ea41c8ecdd0ee77fab3e85263b37e1ec18a3651926b3a6cf27")
+	script, _ := hex.DecodeString("20d85a959b0290bf19bb89ed43c916be835475d013da4b362117393e25a48229b8ac")
+	expected := "bc1pz37fc4cn9ah8anwm4xqqhvxygjf9rjf2resrw8h8w4tmvcs0863sa2e586"
+
+	derived, output, err := BitcoinNetwork.TaprootTreeAddress(internalKey, taproot.NewLeaf(taproot.LeafVersionTapscript, script))
+	if err != nil {
+		t.Fatalf("Error deriving taproot address: %s", err)
+	}
+
+	if derived.Encoded != expected {
+		t.Errorf("Incorrect address. Expected %s, got %s", expected, derived.Encoded)
+	}
+
+	if len(output.Leaves) != 1 || len(output.Leaves[0].ControlBlock) != 33 {
+		t.Errorf("Expected a single leaf with an empty merkle path, got %v", output.Leaves)
+	}
+
+	// A tree address must differ from the key-path only address
+	keyPath, err := BitcoinNetwork.TaprootAddress(internalKey, nil)
+	if err != nil || keyPath.Encoded == derived.Encoded {
+		t.Errorf("Expected different key-path address, got %s / %v", keyPath.Encoded, err)
+	}
+
+	// BIP341 wallet test vector with two leaves
+	internalKey, _ = hex.DecodeString("f9f400803e683727b14f463836e1e78e1c64417638aa066919291a225f0e8dd8")
+	script1, _ := hex.DecodeString("2044b178d64c32c4a05cc4f4d1407268f764c940d20ce97abfd44db5c3592b72fdac")
+	script2, _ := hex.DecodeString("07546170726f6f74")
+	tree := taproot.NewBranch(taproot.NewLeaf(taproot.LeafVersionTapscript, script1), taproot.NewLeaf(taproot.LeafVersionTapscript, script2))
+	expected = "bc1pwl3s54fzmk0cjnpl3w9af39je7pv5ldg504x5guk2hpecpg2kgsqaqstjq"
+	if twoLeaves, _, err := BitcoinNetwork.TaprootTreeAddress(internalKey, tree); err != nil || twoLeaves.Encoded != expected {
+		t.Errorf("Incorrect address. Expected %s, got %s / %v", expected, twoLeaves.Encoded, err)
+	}
+}
@@ -0,0 +1,93 @@
+package addrconv
+
+import (
+	"strings"
+
+	"github.com/coinhako/addrconv/address"
+	"github.com/coinhako/addrconv/base58"
+	"github.com/coinhako/addrconv/cashaddr"
+)
+
+// SuggestCashAddr returns candidate corrections for a cashaddr with a
+// bad checksum, one or two character substitutions away. The prefix may
+// be omitted, in which case every prefix of the network is tried and the
+// suggestions are returned without it too. An explicit prefix must
+// belong to the network. Suggestions must be confirmed by the user, they
+// are never accepted on their own.
+func (network Network) SuggestCashAddr(encodedAddress string) ([]cashaddr.Suggestion, error) {
+	if !network.SupportsCashAddr() {
+		return nil, address.NewError(address.CashAddr, address.ErrUnsupportedEncoding)
+	}
+
+	if separator := strings.LastIndex(encodedAddress, ":"); separator >= 0 {
+		prefix := strings.ToLower(encodedAddress[:separator])
+		for _, allowed := range network.cashAddrPrefixes() {
+			if prefix == allowed {
+				return cashaddr.SuggestCorrections(encodedAddress)
+			}
+		}
+		return nil, address.NewError(address.CashAddr, address.ErrInvalidPrefix)
+	}
+
+	// An address that's valid for one of the prefixes needs no correction
+	if _, err := network.decodeCashAddr(encodedAddress); err == nil {
+		return nil, nil
+	}
+
+	var suggestions []cashaddr.Suggestion
+	for _, prefix := range network.cashAddrPrefixes() {
+		prefixSuggestions, err := suggestPrefixlessCashAddr(encodedAddress, prefix)
+		if err != nil {
+			return nil, err
+		}
+		suggestions = append(suggestions, prefixSuggestions...)
+	}
+
+	return suggestions, nil
+}
+
+func suggestPrefixlessCashAddr(encodedAddress string, prefix string) ([]cashaddr.Suggestion, error) {
+	if strings.ToUpper(encodedAddress) == encodedAddress {
+		prefix = strings.ToUpper(prefix)
+	}
+	addedPrefix := len(prefix) + 1
+
+	suggestions, err := cashaddr.SuggestCorrections(prefix + ":" + encodedAddress)
+	if err != nil {
+		if addrErr, ok := err.(*address.Error); ok && addrErr.Position >= 0 {
+			addrErr.Position -= addedPrefix
+		}
+		return nil, err
+	}
+
+	for i := range suggestions {
+		suggestions[i].Address = suggestions[i].Address[addedPrefix:]
+		for j := range suggestions[i].Positions {
+			suggestions[i].Positions[j] -= addedPrefix
+		}
+	}
+
+	return suggestions, nil
+}
+
+// RecoverBase58 returns candidate corrections for a base58 address that
+// failed to decode: single substitutions, swapped neighbours, and one
+// missing or extra character. A '?' can be used for one unreadable
+// character. Only candidates that decode for this network are returned,
+// and they must be confirmed by the user before use.
+func (network Network) RecoverBase58(encodedAddress string) ([]string, error) {
+	versions := append([]byte{network.PubKeyPrefix, network.ScriptHashPrefix}, network.LegacyScriptHashPrefixes...)
+	candidates, err := base58.Recover(encodedAddress, versions...)
+	if err != nil {
+		return nil, err
+	}
+
+	var recovered []string
+	for _, candidate := range candidates {
+		if _, err := network.decodeBase58(candidate); err == nil {
+			recovered = append(recovered, candidate)
+		}
+	}
+
+	return recovered, nil
+}
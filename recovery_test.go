@@ -0,0 +1,88 @@
+package addrconv
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/coinhako/addrconv/address"
+)
+
+func TestSuggestCashAddr(t *testing.T) {
+	var addresses = []string{"qr7fzmep8g7h7ymfxy74lgc0v950j3r2959lhtxxsm", "bitcoincash:qr7fzmep8g7h7ymfxy74lgc0v950j3r2959lhtxxsm"}
+	var suggested = []string{"qr7fzmep8g7h7ymfxy74lgc0v950j3r2959lhtxxsl", "bitcoincash:qr7fzmep8g7h7ymfxy74lgc0v950j3r2959lhtxxsl"}
+	var positions = []int{41, 53}
+
+	for i, v := range addresses {
+		suggestions, err := BitcoinCashNetwork.SuggestCashAddr(v)
+		if err != nil {
+			t.Errorf("Error suggesting corrections for %s: %s", v, err)
+			continue
+		}
+
+		if len(suggestions) != 1 || suggestions[0].Address != suggested[i] {
+			t.Errorf("Incorrect suggestions for %s. Expected %s, got %v", v, suggested[i], suggestions)
+			continue
+		}
+
+		if suggestions[0].Positions[0] != positions[i] {
+			t.Errorf("Incorrect position for %s. Expected %d, got %d", v, positions[i], suggestions[0].Positions[0])
+		}
+	}
+
+	if _, err := BitcoinNetwork.SuggestCashAddr(addresses[0]); err == nil {
+		t.Errorf("Expected error suggesting cashaddr corrections on bitcoin")
+	}
+
+	// Other chains' prefixes are rejected, like Decode does
+	if _, err := BitcoinCashNetwork.SuggestCashAddr("ecash:qr7fzmep8g7h7ymfxy74lgc0v950j3r295ujrqaukh"); !errors.Is(err, address.ErrInvalidPrefix) {
+		t.Errorf("Expected ErrInvalidPrefix for an ecash address, got %v", err)
+	}
+
+	// Without a prefix, aliases such as SLP addresses are tried as well
+	slpAddress := "qr7fzmep8g7h7ymfxy74lgc0v950j3r295fyusnxwp"
+	suggestions, err := BitcoinCashNetwork.SuggestCashAddr("qr7fzmep8g7h7ymfxy74lgc0v950j3r295fyusnxwq")
+	found := false
+	for _, suggestion := range suggestions {
+		found = found || suggestion.Address == slpAddress
+	}
+	if err != nil || !found {
+		t.Errorf("Expected %s in suggestions, got %v / %v", slpAddress, suggestions, err)
+	}
+}
+
+func TestRecoverBase58(t *testing.T) {
+	validAddress := "1JJ2o6iKB4UXVMHXBSzVvbAKim5su2VUfa"
+	var addresses = []string{
+		"1JJ2o6iKB4UXVMHXBSzVvbAKim5su2VUfb",
+		"1JJ2o6iKB4UXVMHXBSzVvbAKim5su2UVfa",
+		"1JJ2o6iKB4UXVMHXBSzVvbAKim5su2VUa",
+		"1JJ2o6iKB4UXVMHXBSzVvbAKim5su2VUffa",
+		"1JJ2o6iKB4UXVMH?BSzVvbAKim5su2VUfa",
+	}
+
+	for _, v := range addresses {
+		candidates, err := BitcoinNetwork.RecoverBase58(v)
+		if err != nil {
+			t.Errorf("Error recovering %s: %s", v, err)
+			continue
+		}
+
+		found := false
+		for _, candidate := range candidates {
+			found = found || candidate == validAddress
+		}
+
+		if !found {
+			t.Errorf("Expected %s in candidates for %s, got %v", validAddress, v, candidates)
+		}
+	}
+
+	candidates, err := LitecoinNetwork.RecoverBase58(addresses[0])
+	if err != nil || len(candidates) != 0 {
+		t.Errorf("Expected no litecoin candidates for a bitcoin address, got %v / %v", candidates, err)
+	}
+
+	if _, err = BitcoinNetwork.RecoverBase58("1JJ2o6iKB4UXVMH?BSzVvbAKim5su2VU?a"); err == nil {
+		t.Errorf("Expected error recovering an address with two placeholders")
+	}
+}
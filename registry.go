@@ -0,0 +1,165 @@
+package addrconv
+
+import (
+	"errors"
+	"strings"
+	"sync"
+)
+
+// Errors returned by network registries
+var (
+	ErrUnknownNetwork   = errors.New("unknown network")
+	ErrNetworkExists    = errors.New("network name, ticker or alias already registered")
+	ErrInvalidNetwork   = errors.New("network must have a name and a ticker")
+	ErrAmbiguousNetwork = errors.New("several networks registered for coin type")
+)
+
+// Registry holds networks that can be looked up by name, ticker, alias
+// or SLIP-44 coin type. It is safe for concurrent use.
+type Registry struct {
+	mutex      sync.RWMutex
+	networks   []Network
+	byName     map[string]int   // names and aliases
+	byTicker   map[string]int   // tickers and aliases
+	byCoinType map[uint32][]int // every network registered for each coin type
+}
+
+// NewRegistry returns an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		byName:     make(map[string]int),
+		byTicker:   make(map[string]int),
+		byCoinType: make(map[uint32][]int),
+	}
+}
+
+var defaultRegistry = newDefaultRegistry()
+
+// DefaultRegistry returns the registry used by GetNetwork,
+// GetNetworkByTicker, GetNetworkByCoinType, RegisterNetwork and
+// IdentifyNetworks. It starts out holding the KnownNetworks presets.
+func DefaultRegistry() *Registry {
+	return defaultRegistry
+}
+
+func newDefaultRegistry() *Registry {
+	registry := NewRegistry()
+	for _, network := range knownNetworks {
+		if err := registry.Register(network); err != nil {
+			panic(err)
+		}
+	}
+	return registry
+}
+
+// Register adds a network. Names, tickers and aliases are matched case
+// insensitively, and must not clash with any name, ticker or alias of a
+// network already registered, so a name can't resolve to one network
+// and the same string as a ticker to another.
+func (registry *Registry) Register(network Network) error {
+	if network.Name == "" || network.Ticker == "" {
+		return ErrInvalidNetwork
+	}
+
+	names := append([]string{network.Name}, network.Aliases...)
+	tickers := append([]string{network.Ticker}, network.Aliases...)
+
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	for _, key := range append(names, network.Ticker) {
+		key = strings.ToLower(key)
+		_, nameExists := registry.byName[key]
+		_, tickerExists := registry.byTicker[key]
+		if nameExists || tickerExists {
+			return ErrNetworkExists
+		}
+	}
+
+	index := len(registry.networks)
+	registry.networks = append(registry.networks, network)
+	for _, name := range names {
+		registry.byName[strings.ToLower(name)] = index
+	}
+	for _, ticker := range tickers {
+		registry.byTicker[strings.ToLower(ticker)] = index
+	}
+	if network.HasCoinType {
+		registry.byCoinType[network.CoinType] = append(registry.byCoinType[network.CoinType], index)
+	}
+
+	return nil
+}
+
+// Get returns the network with the given name or alias
+func (registry *Registry) Get(name string) (Network, error) {
+	return registry.lookup(registry.byName, strings.ToLower(name))
+}
+
+// GetByTicker returns the network with the given ticker or alias
+func (registry *Registry) GetByTicker(ticker string) (Network, error) {
+	return registry.lookup(registry.byTicker, strings.ToLower(ticker))
+}
+
+// GetByCoinType returns the network registered with the given SLIP-44
+// coin type. Networks without HasCoinType are never matched. Coin types
+// shared by several networks, such as 1 for every testnet, return
+// ErrAmbiguousNetwork.
+func (registry *Registry) GetByCoinType(coinType uint32) (Network, error) {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+
+	indexes := registry.byCoinType[coinType]
+	switch len(indexes) {
+	case 0:
+		return Network{}, ErrUnknownNetwork
+	case 1:
+		return registry.networks[indexes[0]], nil
+	}
+	return Network{}, ErrAmbiguousNetwork
+}
+
+// Networks returns every registered network, in registration order
+func (registry *Registry) Networks() []Network {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+
+	networks := make([]Network, len(registry.networks))
+	copy(networks, registry.networks)
+	return networks
+}
+
+func (registry *Registry) lookup(index map[string]int, key string) (Network, error) {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+
+	i, ok := index[key]
+	if !ok {
+		return Network{}, ErrUnknownNetwork
+	}
+	return registry.networks[i], nil
+}
+
+// RegisterNetwork adds a custom network to the default registry
+func RegisterNetwork(network Network) error {
+	return defaultRegistry.Register(network)
+}
+
+// GetNetwork returns the registered network for a coin name, such as
+// "bitcoin", or ErrUnknownNetwork
+func GetNetwork(name string) (Network, error) {
+	return defaultRegistry.Get(name)
+}
+
+// GetNetworkByTicker returns the registered network for a ticker, such
+// as "btc", or ErrUnknownNetwork
+func GetNetworkByTicker(ticker string) (Network, error) {
+	return defaultRegistry.GetByTicker(ticker)
+}
+
+// GetNetworkByCoinType returns the registered network for a SLIP-44 coin
+// type, ErrUnknownNetwork, or ErrAmbiguousNetwork for shared coin types
+// such as the testnets' 1
+func GetNetworkByCoinType(coinType uint32) (Network, error) {
+	return defaultRegistry.GetByCoinType(coinType)
+}
@@ -0,0 +1,126 @@
+package addrconv
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestGetNetwork(t *testing.T) {
+	var names = []string{"bitcoin", "Litecoin", "DOGECOIN", "firo", "ecash"}
+	var tickers = []string{"btc", "LTC", "doge", "FIRO", "xec"}
+	var coinTypes = []uint32{0, 2, 3, 136, 899}
+	var expected = []string{"bitcoin", "litecoin", "dogecoin", "zcoin", "ecash"}
+
+	for i := range names {
+		network, err := GetNetwork(names[i])
+		if err != nil || network.Name != expected[i] {
+			t.Errorf("Incorrect network for name %s. Expected %s, got %s / %v", names[i], expected[i], network.Name, err)
+		}
+
+		network, err = GetNetworkByTicker(tickers[i])
+		if err != nil || network.Name != expected[i] {
+			t.Errorf("Incorrect network for ticker %s. Expected %s, got %s / %v", tickers[i], expected[i], network.Name, err)
+		}
+
+		network, err = GetNetworkByCoinType(coinTypes[i])
+		if err != nil || network.Name != expected[i] {
+			t.Errorf("Incorrect network for coin type %d. Expected %s, got %s / %v", coinTypes[i], expected[i], network.Name, err)
+		}
+	}
+
+	// A name can't take another network's ticker
+	if err := RegisterNetwork(Network{Name: "btc", Ticker: "zzz"}); err != ErrNetworkExists {
+		t.Errorf("Expected ErrNetworkExists, got %v", err)
+	}
+
+	// Typos must not fall back to bitcoin
+	if _, err := GetNetwork("dogecion"); err != ErrUnknownNetwork {
+		t.Errorf("Expected ErrUnknownNetwork, got %v", err)
+	}
+	if _, err := GetNetworkByTicker("btcc"); err != ErrUnknownNetwork {
+		t.Errorf("Expected ErrUnknownNetwork, got %v", err)
+	}
+	if _, err := GetNetworkByCoinType(999999); err != ErrUnknownNetwork {
+		t.Errorf("Expected ErrUnknownNetwork, got %v", err)
+	}
+
+	// Every testnet uses coin type 1
+	if _, err := GetNetworkByCoinType(1); err != ErrAmbiguousNetwork {
+		t.Errorf("Expected ErrAmbiguousNetwork, got %v", err)
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry()
+
+	custom := Network{
+		Name:             "examplecoin",
+		Ticker:           "exc",
+		Aliases:          []string{"example"},
+		CoinType:         12345,
+		HasCoinType:      true,
+		PubKeyPrefix:     0x21,
+		ScriptHashPrefix: 0x22,
+	}
+	if err := registry.Register(custom); err != nil {
+		t.Fatalf("Error registering network: %s", err)
+	}
+
+	for _, v := range []string{"examplecoin", "EXAMPLE"} {
+		if network, err := registry.Get(v); err != nil || network.Name != custom.Name {
+			t.Errorf("Incorrect network for %s: %s / %v", v, network.Name, err)
+		}
+	}
+
+	var clashes = []Network{
+		{Name: "ExampleCoin", Ticker: "new"},
+		{Name: "new", Ticker: "EXC"},
+		{Name: "new", Ticker: "new", Aliases: []string{"example"}},
+		{Name: "exc", Ticker: "new"},
+		{Name: "new", Ticker: "examplecoin"},
+	}
+	for _, network := range clashes {
+		if err := registry.Register(network); err != ErrNetworkExists {
+			t.Errorf("Expected ErrNetworkExists registering %s/%s, got %v", network.Name, network.Ticker, err)
+		}
+	}
+
+	if err := registry.Register(Network{Name: "noticker"}); err != ErrInvalidNetwork {
+		t.Errorf("Expected ErrInvalidNetwork, got %v", err)
+	}
+
+	// The default registry isn't affected
+	if _, err := GetNetwork("examplecoin"); err != ErrUnknownNetwork {
+		t.Errorf("Expected ErrUnknownNetwork from the default registry, got %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			name := fmt.Sprintf("coin%d", i)
+			if err := registry.Register(Network{Name: name, Ticker: name}); err != nil {
+				t.Errorf("Error registering %s: %s", name, err)
+			}
+			if _, err := registry.Get(name); err != nil {
+				t.Errorf("Error getting %s: %s", name, err)
+			}
+			registry.Networks()
+		}(i)
+	}
+	wg.Wait()
+
+	if len(registry.Networks()) != 21 {
+		t.Errorf("Incorrect number of networks. Expected 21, got %d", len(registry.Networks()))
+	}
+
+	// None of the coin%d networks have a coin type, so they aren't bitcoin
+	if _, err := registry.GetByCoinType(0); err != ErrUnknownNetwork {
+		t.Errorf("Expected ErrUnknownNetwork for coin type 0, got %v", err)
+	}
+	if network, err := registry.GetByCoinType(custom.CoinType); err != nil || network.Name != custom.Name {
+		t.Errorf("Incorrect network for coin type %d: %s / %v", custom.CoinType, network.Name, err)
+	}
+}
@@ -0,0 +1,147 @@
+package addrconv
+
+import (
+	"strings"
+
+	"github.com/coinhako/addrconv/address"
+	"github.com/coinhako/blockutils"
+)
+
+// Opcodes used by the standard output scripts
+const (
+	opDup           = 0x76
+	opHash160       = 0xa9
+	opHash256       = 0xaa
+	opEqual         = 0x87
+	opEqualVerify   = 0x88
+	opCheckSig      = 0xac
+	opCheckMultisig = 0xae
+	opReturn        = 0x6a
+	op0             = 0x00
+	op1             = 0x51
+)
+
+// ToScript builds the canonical output script (scriptPubKey) that pays
+// to a decoded address. Addresses carrying a bech32 HRP, cashaddr prefix
+// or base58 version byte of another network are rejected.
+//
+// P2PKH and P2SH addresses without a cashaddr prefix are base58, so
+// their Version must be one of the network's prefixes. Decode, FromScript
+// and the address constructors set it; hand-built addresses must too,
+// since the zero value is only bitcoin's P2PKH version.
+func (network Network) ToScript(decodedAddress address.Address) (blockutils.Script, error) {
+	if err := network.checkAddressNetwork(decodedAddress); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case decodedAddress.Type == address.P2PKH:
+		if err := checkHashLength(decodedAddress, 20); err != nil {
+			return nil, err
+		}
+		script := []byte{opDup, opHash160, 20}
+		script = append(script, decodedAddress.Hash...)
+		return append(script, opEqualVerify, opCheckSig), nil
+
+	case decodedAddress.Type == address.P2PK:
+		if len(decodedAddress.PubKeys) != 1 {
+			return nil, address.NewError(address.NoEncoding, address.ErrInvalidData)
+		}
+		script := append([]byte{byte(len(decodedAddress.PubKeys[0]))}, decodedAddress.PubKeys[0]...)
+		script = append(script, opCheckSig)
+		if _, ok := parseP2PKScript(script); !ok {
+			return nil, address.NewError(address.NoEncoding, address.ErrInvalidData)
+		}
+		return script, nil
+
+	case decodedAddress.Type == address.P2MS:
+		script := buildMultisigScript(decodedAddress.Threshold, decodedAddress.PubKeys)
+		if _, _, ok := parseMultisigScript(script); !ok {
+			return nil, address.NewError(address.NoEncoding, address.ErrInvalidData)
+		}
+		return script, nil
+
+	case decodedAddress.IsP2SH():
+		if err := checkHashLength(decodedAddress, 20); err != nil {
+			return nil, err
+		}
+		script := []byte{opHash160, 20}
+		script = append(script, decodedAddress.Hash...)
+		return append(script, opEqual), nil
+
+	case decodedAddress.Type == address.P2SH32:
+		if err := checkHashLength(decodedAddress, 32); err != nil {
+			return nil, err
+		}
+		script := []byte{opHash256, 32}
+		script = append(script, decodedAddress.Hash...)
+		return append(script, opEqual), nil
+
+	case decodedAddress.Type == address.P2WPKH:
+		if err := checkHashLength(decodedAddress, 20); err != nil {
+			return nil, err
+		}
+		return append([]byte{op0, 20}, decodedAddress.Hash...), nil
+
+	case decodedAddress.Type == address.P2WSH:
+		if err := checkHashLength(decodedAddress, 32); err != nil {
+			return nil, err
+		}
+		return append([]byte{op0, 32}, decodedAddress.Hash...), nil
+
+	case decodedAddress.Type == address.P2TR:
+		if err := checkHashLength(decodedAddress, 32); err != nil {
+			return nil, err
+		}
+		return append([]byte{op1, 32}, decodedAddress.Hash...), nil
+	}
+
+	return nil, address.NewError(address.NoEncoding, address.ErrUnsupportedType)
+}
+
+// DecodeToScript decodes an address string for the network and returns
+// the output script that pays to it
+func (network Network) DecodeToScript(encodedAddress string) (blockutils.Script, error) {
+	decodedAddress, err := network.Decode(encodedAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	return network.ToScript(decodedAddress)
+}
+
+func checkHashLength(decodedAddress address.Address, length int) error {
+	if len(decodedAddress.Hash) != length {
+		return address.NewError(address.NoEncoding, address.ErrInvalidLength)
+	}
+	return nil
+}
+
+// checkAddressNetwork checks that whatever network information the
+// address carries belongs to this network
+func (network Network) checkAddressNetwork(decodedAddress address.Address) error {
+	if decodedAddress.Bech32HRP != "" && strings.ToLower(decodedAddress.Bech32HRP) != network.Bech32Prefix {
+		return address.NewError(address.Bech32, address.ErrInvalidPrefix)
+	}
+
+	if decodedAddress.CashAddrPrefix != "" {
+		prefix := strings.ToLower(decodedAddress.CashAddrPrefix)
+		for _, allowed := range network.cashAddrPrefixes() {
+			if prefix == allowed {
+				return nil
+			}
+		}
+		return address.NewError(address.CashAddr, address.ErrInvalidPrefix)
+	}
+
+	// Without a cashaddr prefix, P2PKH and P2SH addresses are base58
+	// and their version byte must be one of ours
+	switch {
+	case decodedAddress.Type == address.P2PKH && decodedAddress.Version != network.PubKeyPrefix:
+		return address.NewError(address.Base58, address.ErrInvalidVersion)
+	case decodedAddress.IsP2SH() && decodedAddress.Version != network.ScriptHashPrefix && !network.isLegacyScriptHashPrefix(decodedAddress.Version):
+		return address.NewError(address.Base58, address.ErrInvalidVersion)
+	}
+
+	return nil
+}
@@ -0,0 +1,244 @@
+package addrconv
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/coinhako/addrconv/address"
+	"github.com/coinhako/addrconv/nulldata"
+)
+
+func TestToScriptRoundTrip(t *testing.T) {
+	var base58Scripts = []string{
+		"76a914bdb2b538e6b07e93d6bafcef4bec9dc936818a1988ac",
+		"a9144aef67ed61d391d6f3d9903ead92386c1efc992587",
+	}
+	var bech32Scripts = []string{
+		"0014751e76e8199196d454941c45d1b3a323f1433bd6",
+		"0020701a8d401c84fb13e6baf169d59684e17abd9fa216c8cc5b9fc63d622ff8c58d",
+		"512079be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+	}
+	var cashAddrScripts = []string{
+		"aa203173ef6623c6b48ffd1a3dcc0cc6489b0a07bb47a37f47cfef4fe69de825c06087",
+	}
+
+	for _, network := range KnownNetworks() {
+		scripts := base58Scripts
+		if network.SupportsBech32() {
+			scripts = append(scripts, bech32Scripts...)
+		}
+		if network.SupportsCashAddr() {
+			scripts = append(scripts, cashAddrScripts...)
+		}
+
+		for _, v := range scripts {
+			script, err := hex.DecodeString(v)
+			if err != nil {
+				t.Errorf("Error decoding hex: %s", err)
+			}
+
+			encodedAddress, err := network.Encode(script)
+			if err != nil {
+				t.Errorf("Error encoding address: %s", err)
+			}
+
+			decodedScript, err := network.DecodeToScript(encodedAddress)
+			if err != nil {
+				t.Errorf("Error decoding address %s: %s", encodedAddress, err)
+			}
+
+			if decodedScript.String() != v {
+				t.Errorf("Incorrect script for %s. Expected %s, got %s", encodedAddress, v, decodedScript)
+			}
+		}
+	}
+}
+
+func TestToScriptCashAddr(t *testing.T) {
+	var addresses = []string{"bitcoincash:qr7fzmep8g7h7ymfxy74lgc0v950j3r2959lhtxxsl", "bitcoincash:zr7fzmep8g7h7ymfxy74lgc0v950j3r295z4y4gq0v", "bitcoincash:pp9w7eldv8fer4hnmxgratvj8pkpalyey5qym9j8x5"}
+	var scripts = []string{"76a914fc916f213a3d7f1369313d5fa30f6168f9446a2d88ac", "76a914fc916f213a3d7f1369313d5fa30f6168f9446a2d88ac", "a9144aef67ed61d391d6f3d9903ead92386c1efc992587"}
+
+	for i, v := range addresses {
+		script, err := BitcoinCashNetwork.DecodeToScript(v)
+		if err != nil {
+			t.Errorf("Error decoding address %s: %s", v, err)
+		}
+
+		if script.String() != scripts[i] {
+			t.Errorf("Incorrect script for %s. Expected %s, got %s", v, scripts[i], script)
+		}
+	}
+
+	var decodedAddress address.Address
+	decodedAddress.Type = address.P2WPKH
+	decodedAddress.Hash = make([]byte, 32)
+	if _, err := BitcoinNetwork.ToScript(decodedAddress); err == nil {
+		t.Errorf("Expected error for P2WPKH address with a 32 byte hash")
+	}
+}
+
+func TestFromScriptP2PK(t *testing.T) {
+	var scripts = []string{
+		"4104f601d3111e0f502f8d5927fd4077e8723f9b156138a776afa5ceae4d6da7370d4f1effb4171cd8ce7b40d54e3a0b45b528575ce63465986810085babdef06f01ac",
+		"2103f601d3111e0f502f8d5927fd4077e8723f9b156138a776afa5ceae4d6da7370dac",
+	}
+	var addresses = []string{"18AuG6THx5V9JuZbtWtrabYWfxY28YcXhN", "1ABTTFaiYdTNX4yzQ1vn5QP1URrHwCDbFc"}
+
+	for i, v := range scripts {
+		script, _ := hex.DecodeString(v)
+		decodedAddress, err := BitcoinNetwork.FromScript(script)
+		if err != nil {
+			t.Errorf("Error classifying script %s: %s", v, err)
+			continue
+		}
+
+		if decodedAddress.Type != address.P2PK {
+			t.Errorf("Incorrect address type. Expected %d, got %d", address.P2PK, decodedAddress.Type)
+		}
+
+		if len(decodedAddress.PubKeys) != 1 || hex.EncodeToString(decodedAddress.PubKeys[0]) != v[2:len(v)-2] {
+			t.Errorf("Incorrect public key for %s, got %x", v, decodedAddress.PubKeys)
+		}
+
+		// The P2PKH address is only given when asked for
+		encodedAddress, err := BitcoinNetwork.EncodeToBase58(decodedAddress)
+		if err != nil || encodedAddress != addresses[i] {
+			t.Errorf("Incorrect address. Expected %s, got %s / %v", addresses[i], encodedAddress, err)
+		}
+
+		roundTrip, err := BitcoinNetwork.ToScript(decodedAddress)
+		if err != nil || roundTrip.String() != v {
+			t.Errorf("Incorrect script. Expected %s, got %s / %v", v, roundTrip, err)
+		}
+	}
+
+	var otherScripts = []string{"76a914bdb2b538e6b07e93d6bafcef4bec9dc936818a1988ac", "0014751e76e8199196d454941c45d1b3a323f1433bd6"}
+	var types = []address.AddressType{address.P2PKH, address.P2WPKH}
+	for i, v := range otherScripts {
+		script, _ := hex.DecodeString(v)
+		decodedAddress, err := BitcoinNetwork.FromScript(script)
+		if err != nil || decodedAddress.Type != types[i] {
+			t.Errorf("Incorrect address type for %s. Expected %d, got %d / %v", v, types[i], decodedAddress.Type, err)
+		}
+	}
+
+	script, _ := hex.DecodeString("6a0548656c6c6f")
+	if _, err := BitcoinNetwork.FromScript(script); err == nil {
+		t.Errorf("Expected error for OP_RETURN script")
+	}
+}
+
+func TestEncodeScript(t *testing.T) {
+	var scripts = []string{
+		"76a914bdb2b538e6b07e93d6bafcef4bec9dc936818a1988ac",
+		"0014751e76e8199196d454941c45d1b3a323f1433bd6",
+		"512079be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+		"5220751e76e8199196d454941c45d1b3a323f1433bd6751e76e8199196d454941c45",
+		"2103f601d3111e0f502f8d5927fd4077e8723f9b156138a776afa5ceae4d6da7370dac",
+		"410679be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8ac",
+		"6a146f6d6e69000000000000001f0000000005f5e100",
+		"6a05aabb",
+		"ac",
+		"",
+	}
+	var types = []address.AddressType{address.P2PKH, address.P2WPKH, address.P2TR, address.WITNESS_UNKNOWN, address.P2PK, address.P2PK, address.NULL_DATA, address.NULL_DATA, address.NONSTANDARD, address.NONSTANDARD}
+	var encodings = []address.Encoding{address.Base58, address.Bech32, address.Bech32m, address.Bech32m, address.NoEncoding, address.NoEncoding, address.NoEncoding, address.NoEncoding, address.NoEncoding, address.NoEncoding}
+	var standard = []bool{true, true, true, true, true, true, true, false, false, false}
+
+	for i, v := range scripts {
+		script, _ := hex.DecodeString(v)
+		result, err := BitcoinNetwork.EncodeScript(script)
+		if err != nil {
+			t.Errorf("Error encoding script %s: %s", v, err)
+			continue
+		}
+
+		if result.Address.Type != types[i] || result.Encoding != encodings[i] || result.Standard != standard[i] {
+			t.Errorf("Incorrect result for %s. Expected %d/%s/%t, got %d/%s/%t", v, types[i], encodings[i], standard[i], result.Address.Type, result.Encoding, result.Standard)
+		}
+
+		if (result.Encoded != "") != (encodings[i] != address.NoEncoding) {
+			t.Errorf("Incorrect address string %q for %s", result.Encoded, v)
+		}
+
+		if result.Network.Name != BitcoinNetwork.Name {
+			t.Errorf("Incorrect network. Expected %s, got %s", BitcoinNetwork.Name, result.Network.Name)
+		}
+	}
+
+	script, _ := hex.DecodeString(scripts[6])
+	result, _ := BitcoinNetwork.EncodeScript(script)
+	if result.NullData == nil || result.NullData.Protocol != nulldata.Omni {
+		t.Errorf("Expected omni null data, got %v", result.NullData)
+	}
+
+	// Segwit outputs have no address on networks without bech32
+	script, _ = hex.DecodeString(scripts[1])
+	result, err := DogecoinNetwork.EncodeScript(script)
+	if err != nil || result.Address.Type != address.P2WPKH || result.Encoded != "" || result.Standard {
+		t.Errorf("Expected P2WPKH without an address on dogecoin, got %v / %v", result, err)
+	}
+}
+
+func TestToScriptWrongNetwork(t *testing.T) {
+	var networks = []Network{LitecoinNetwork, LitecoinNetwork, BitcoinCashNetwork, ECashNetwork}
+	var addresses = []string{
+		"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4",
+		"1BpEi6DfDAUFd7GtittLSdBeYJvcoaVggu",
+		"bitcoincash:qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a",
+		"bitcoincash:qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a",
+	}
+	var sources = []Network{BitcoinNetwork, BitcoinNetwork, BitcoinCashNetwork, BitcoinCashNetwork}
+	var reasons = []error{address.ErrInvalidPrefix, address.ErrInvalidVersion, nil, address.ErrInvalidPrefix}
+
+	for i, v := range addresses {
+		decodedAddress, err := sources[i].Decode(v)
+		if err != nil {
+			t.Errorf("Error decoding %s: %s", v, err)
+			continue
+		}
+
+		_, err = networks[i].ToScript(decodedAddress)
+		if reasons[i] == nil && err != nil {
+			t.Errorf("Error building script for %s on %s: %s", v, networks[i].Name, err)
+		}
+		if reasons[i] != nil && !errors.Is(err, reasons[i]) {
+			t.Errorf("Incorrect error for %s on %s. Expected %s, got %v", v, networks[i].Name, reasons[i], err)
+		}
+	}
+
+	// Hand-built base58 addresses need the network's version byte
+	var handBuilt address.Address
+	handBuilt.Type = address.P2PKH
+	handBuilt.Hash, _ = hex.DecodeString("bdb2b538e6b07e93d6bafcef4bec9dc936818a19")
+	if _, err := LitecoinNetwork.ToScript(handBuilt); !errors.Is(err, address.ErrInvalidVersion) {
+		t.Errorf("Expected ErrInvalidVersion without a version byte, got %v", err)
+	}
+
+	handBuilt.Version = LitecoinNetwork.PubKeyPrefix
+	if script, err := LitecoinNetwork.ToScript(handBuilt); err != nil || script.String() != "76a914bdb2b538e6b07e93d6bafcef4bec9dc936818a1988ac" {
+		t.Errorf("Incorrect script for a litecoin P2PKH address: %s / %v", script, err)
+	}
+}
+
+func TestNullDataSize(t *testing.T) {
+	// 100 bytes of data is too much for bitcoin, but fine on bitcoin cash
+	script, _ := hex.DecodeString("6a4c64" + strings.Repeat("ab", 100))
+	var networks = []Network{BitcoinNetwork, BitcoinCashNetwork, ECashNetwork, {Name: "custom"}}
+	var standard = []bool{false, true, true, false}
+
+	for i, network := range networks {
+		result, err := network.EncodeScript(script)
+		if err != nil || result.Address.Type != address.NULL_DATA || result.Standard != standard[i] {
+			t.Errorf("Incorrect null data result on %s. Expected standard %t, got %t / %v", network.Name, standard[i], result.Standard, err)
+		}
+	}
+
+	// The bitcoin cash limit is 223 bytes of script
+	script, _ = hex.DecodeString("6a4cdd" + strings.Repeat("ab", 221))
+	if result, _ := BitcoinCashNetwork.EncodeScript(script); result.Standard {
+		t.Errorf("Expected a %d byte null data output to be nonstandard on bitcoin cash", len(script))
+	}
+}
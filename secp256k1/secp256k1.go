@@ -0,0 +1,195 @@
+// Package secp256k1 implements the public key operations needed to derive
+// addresses: parsing and serializing keys, and the point arithmetic used
+// by taproot tweaks. It only ever handles public data, so it makes no
+// attempt to be constant time and must not be used with private keys.
+package secp256k1
+
+import (
+	"errors"
+	"math/big"
+)
+
+var (
+	// Field prime, group order and generator
+	curveP, _  = new(big.Int).SetString("fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f", 16)
+	curveN, _  = new(big.Int).SetString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141", 16)
+	curveGx, _ = new(big.Int).SetString("79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798", 16)
+	curveGy, _ = new(big.Int).SetString("483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8", 16)
+	curveB     = big.NewInt(7)
+)
+
+var (
+	ErrInvalidPublicKey = errors.New("invalid public key")
+	ErrInvalidScalar    = errors.New("invalid scalar")
+	ErrPointAtInfinity  = errors.New("point at infinity")
+)
+
+// PublicKey is a point on the curve, never the point at infinity
+type PublicKey struct {
+	X *big.Int
+	Y *big.Int
+}
+
+// ParsePublicKey parses a 33 byte compressed, 65 byte uncompressed or
+// 32 byte x-only (BIP340, even Y) public key and checks it is on the curve
+func ParsePublicKey(pubKey []byte) (*PublicKey, error) {
+	switch {
+	case len(pubKey) == 65 && pubKey[0] == 0x04:
+		x := new(big.Int).SetBytes(pubKey[1:33])
+		y := new(big.Int).SetBytes(pubKey[33:])
+		if x.Cmp(curveP) >= 0 || y.Cmp(curveP) >= 0 {
+			return nil, ErrInvalidPublicKey
+		}
+		if new(big.Int).Exp(y, big.NewInt(2), curveP).Cmp(curveRHS(x)) != 0 {
+			return nil, ErrInvalidPublicKey
+		}
+		return &PublicKey{X: x, Y: y}, nil
+
+	case len(pubKey) == 33 && (pubKey[0] == 0x02 || pubKey[0] == 0x03):
+		return liftX(pubKey[1:], pubKey[0] == 0x03)
+
+	case len(pubKey) == 32:
+		return liftX(pubKey, false)
+	}
+
+	return nil, ErrInvalidPublicKey
+}
+
+// liftX finds the point with the given x coordinate and y parity
+func liftX(xBytes []byte, odd bool) (*PublicKey, error) {
+	x := new(big.Int).SetBytes(xBytes)
+	if x.Cmp(curveP) >= 0 {
+		return nil, ErrInvalidPublicKey
+	}
+
+	// p = 3 mod 4, so the square root is c^((p+1)/4)
+	c := curveRHS(x)
+	exp := new(big.Int).Add(curveP, big.NewInt(1))
+	exp.Rsh(exp, 2)
+	y := new(big.Int).Exp(c, exp, curveP)
+	if new(big.Int).Exp(y, big.NewInt(2), curveP).Cmp(c) != 0 {
+		return nil, ErrInvalidPublicKey
+	}
+
+	if (y.Bit(0) == 1) != odd {
+		y.Sub(curveP, y)
+	}
+
+	return &PublicKey{X: x, Y: y}, nil
+}
+
+// curveRHS computes x^3 + 7 mod p
+func curveRHS(x *big.Int) *big.Int {
+	rhs := new(big.Int).Exp(x, big.NewInt(3), curveP)
+	rhs.Add(rhs, curveB)
+	return rhs.Mod(rhs, curveP)
+}
+
+// SerializeCompressed returns the 33 byte SEC encoding of the key
+func (key *PublicKey) SerializeCompressed() []byte {
+	buf := make([]byte, 33)
+	buf[0] = 0x02
+	if !key.HasEvenY() {
+		buf[0] = 0x03
+	}
+	fillBytes(key.X, buf[1:])
+	return buf
+}
+
+// SerializeUncompressed returns the 65 byte SEC encoding of the key
+func (key *PublicKey) SerializeUncompressed() []byte {
+	buf := make([]byte, 65)
+	buf[0] = 0x04
+	fillBytes(key.X, buf[1:33])
+	fillBytes(key.Y, buf[33:])
+	return buf
+}
+
+// XOnly returns the 32 byte BIP340 encoding of the key
+func (key *PublicKey) XOnly() []byte {
+	buf := make([]byte, 32)
+	fillBytes(key.X, buf)
+	return buf
+}
+
+// fillBytes writes n big-endian into buf, left padded with zeros. It is
+// big.Int.FillBytes, which needs Go 1.15.
+func fillBytes(n *big.Int, buf []byte) {
+	for i := range buf {
+		buf[i] = 0
+	}
+	b := n.Bytes()
+	copy(buf[len(buf)-len(b):], b)
+}
+
+// HasEvenY reports whether the Y coordinate is even
+func (key *PublicKey) HasEvenY() bool {
+	return key.Y.Bit(0) == 0
+}
+
+// Add returns key + other
+func (key *PublicKey) Add(other *PublicKey) (*PublicKey, error) {
+	x, y := addPoints(key.X, key.Y, other.X, other.Y)
+	if x == nil {
+		return nil, ErrPointAtInfinity
+	}
+	return &PublicKey{X: x, Y: y}, nil
+}
+
+// ScalarBaseMult returns k*G for a 32 byte big endian scalar 0 < k < n
+func ScalarBaseMult(scalar []byte) (*PublicKey, error) {
+	k := new(big.Int).SetBytes(scalar)
+	if len(scalar) != 32 || k.Sign() == 0 || k.Cmp(curveN) >= 0 {
+		return nil, ErrInvalidScalar
+	}
+
+	var x, y *big.Int
+	for i := k.BitLen() - 1; i >= 0; i-- {
+		x, y = addPoints(x, y, x, y)
+		if k.Bit(i) == 1 {
+			x, y = addPoints(x, y, curveGx, curveGy)
+		}
+	}
+
+	return &PublicKey{X: x, Y: y}, nil
+}
+
+// addPoints adds two affine points, nil coordinates are the point at infinity
+func addPoints(x1, y1, x2, y2 *big.Int) (*big.Int, *big.Int) {
+	if x1 == nil {
+		return x2, y2
+	}
+	if x2 == nil {
+		return x1, y1
+	}
+
+	var lambda *big.Int
+	if x1.Cmp(x2) == 0 {
+		if y1.Cmp(y2) != 0 || y1.Sign() == 0 {
+			return nil, nil
+		}
+		// Doubling: lambda = 3x^2 / 2y
+		num := new(big.Int).Mul(x1, x1)
+		num.Mul(num, big.NewInt(3))
+		den := new(big.Int).Lsh(y1, 1)
+		lambda = num.Mul(num, den.ModInverse(den.Mod(den, curveP), curveP))
+	} else {
+		// lambda = (y2 - y1) / (x2 - x1)
+		num := new(big.Int).Sub(y2, y1)
+		den := new(big.Int).Sub(x2, x1)
+		lambda = num.Mul(num, den.ModInverse(den.Mod(den, curveP), curveP))
+	}
+	lambda.Mod(lambda, curveP)
+
+	x3 := new(big.Int).Mul(lambda, lambda)
+	x3.Sub(x3, x1)
+	x3.Sub(x3, x2)
+	x3.Mod(x3, curveP)
+
+	y3 := new(big.Int).Sub(x1, x3)
+	y3.Mul(y3, lambda)
+	y3.Sub(y3, y1)
+	y3.Mod(y3, curveP)
+
+	return x3, y3
+}
@@ -0,0 +1,65 @@
+package secp256k1
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestScalarBaseMult(t *testing.T) {
+	var scalars = []string{
+		"0000000000000000000000000000000000000000000000000000000000000001",
+		"0000000000000000000000000000000000000000000000000000000000000002",
+		"0000000000000000000000000000000000000000000000000000000000000003",
+	}
+	var pubKeys = []string{
+		"0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+		"02c6047f9441ed7d6d3045406e95c07cd85c778e4b8cef3ca7abac09b95c709ee5",
+		"02f9308a019258c31049344f85f89d5229b531c845836f99b08601f113bce036f9",
+	}
+
+	for i, v := range scalars {
+		scalar, _ := hex.DecodeString(v)
+		key, err := ScalarBaseMult(scalar)
+		if err != nil {
+			t.Errorf("Error multiplying %s: %s", v, err)
+			continue
+		}
+
+		if hex.EncodeToString(key.SerializeCompressed()) != pubKeys[i] {
+			t.Errorf("Incorrect public key. Expected %s, got %x", pubKeys[i], key.SerializeCompressed())
+		}
+	}
+
+	if _, err := ScalarBaseMult(make([]byte, 32)); err == nil {
+		t.Errorf("Expected error multiplying by zero")
+	}
+}
+
+func TestParsePublicKey(t *testing.T) {
+	compressed, _ := hex.DecodeString("0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798")
+	uncompressed, _ := hex.DecodeString("0479be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8")
+
+	key, err := ParsePublicKey(compressed)
+	if err != nil {
+		t.Errorf("Error parsing compressed key: %s", err)
+	}
+
+	if hex.EncodeToString(key.SerializeUncompressed()) != hex.EncodeToString(uncompressed) {
+		t.Errorf("Incorrect uncompressed key. Expected %x, got %x", uncompressed, key.SerializeUncompressed())
+	}
+
+	key, err = ParsePublicKey(uncompressed)
+	if err != nil {
+		t.Errorf("Error parsing uncompressed key: %s", err)
+	}
+
+	if hex.EncodeToString(key.SerializeCompressed()) != hex.EncodeToString(compressed) {
+		t.Errorf("Incorrect compressed key. Expected %x, got %x", compressed, key.SerializeCompressed())
+	}
+
+	// Not on the curve
+	uncompressed[64] ^= 0x01
+	if _, err = ParsePublicKey(uncompressed); err == nil {
+		t.Errorf("Expected error parsing a point that is not on the curve")
+	}
+}
@@ -0,0 +1,70 @@
+// Package taproot implements the BIP341 output key tweak used to build
+// P2TR addresses from an internal public key.
+package taproot
+
+import (
+	"crypto/sha256"
+	"errors"
+
+	"github.com/coinhako/addrconv/secp256k1"
+)
+
+// ErrInvalidMerkleRoot is returned for a script tree root that isn't 32 bytes
+var ErrInvalidMerkleRoot = errors.New("taproot: invalid merkle root")
+
+// TaggedHash computes the BIP340 tagged hash
+// sha256(sha256(tag) || sha256(tag) || msg)
+func TaggedHash(tag string, msgs ...[]byte) []byte {
+	tagHash := sha256.Sum256([]byte(tag))
+	hash := sha256.New()
+	hash.Write(tagHash[:])
+	hash.Write(tagHash[:])
+	for _, msg := range msgs {
+		hash.Write(msg)
+	}
+	return hash.Sum(nil)
+}
+
+// OutputKey returns the x-only output key for a taproot output that can
+// only be spent through the key path (BIP86). The internal key can be a
+// compressed, uncompressed or x-only public key.
+func OutputKey(internalKey []byte) ([]byte, error) {
+	outputKey, _, err := TweakPublicKey(internalKey, nil)
+	return outputKey, err
+}
+
+// TweakPublicKey computes the BIP341 output key
+// Q = P + H_TapTweak(P || merkleRoot)·G, where P is the internal key with
+// an even Y coordinate. A nil merkle root tweaks for a key-path only
+// output. It returns the x-only output key and whether Q has an odd Y
+// coordinate, which script path spends need for the control block.
+func TweakPublicKey(internalKey []byte, merkleRoot []byte) (outputKey []byte, oddY bool, err error) {
+	if merkleRoot != nil && len(merkleRoot) != 32 {
+		return nil, false, ErrInvalidMerkleRoot
+	}
+
+	key, err := secp256k1.ParsePublicKey(internalKey)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// BIP341 only uses the x coordinate of the internal key
+	xOnly := key.XOnly()
+	key, err = secp256k1.ParsePublicKey(xOnly)
+	if err != nil {
+		return nil, false, err
+	}
+
+	tweak := TaggedHash("TapTweak", xOnly, merkleRoot)
+	tweakPoint, err := secp256k1.ScalarBaseMult(tweak)
+	if err != nil {
+		return nil, false, err
+	}
+
+	tweaked, err := key.Add(tweakPoint)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return tweaked.XOnly(), !tweaked.HasEvenY(), nil
+}
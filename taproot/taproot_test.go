@@ -0,0 +1,60 @@
+package taproot
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestTweakPublicKey(t *testing.T) {
+	// BIP341 wallet test vectors
+	var internalKeys = []string{
+		"d6889cb081036e0faefa3a35157ad71086b123b2b144b649798b494c300a961d",
+		"187791b6f712a8ea41c8ecdd0ee77fab3e85263b37e1ec18a3651926b3a6cf27",
+	}
+	var merkleRoots = []string{
+		"",
+		"5b75adecf53548f3ec6ad7d78383bf84cc57b55a3127c72b9a2481752dd88b21",
+	}
+	var outputKeys = []string{
+		"53a1f6e454df1aa2776a2814a721372d6258050de330b3c6d10ee8f4e0dda343",
+		"147c9c57132f6e7ecddba9800bb0c4449251c92a1e60371ee77557b6620f3ea3",
+	}
+
+	for i, v := range internalKeys {
+		internalKey, _ := hex.DecodeString(v)
+		var merkleRoot []byte
+		if merkleRoots[i] != "" {
+			merkleRoot, _ = hex.DecodeString(merkleRoots[i])
+		}
+
+		outputKey, _, err := TweakPublicKey(internalKey, merkleRoot)
+		if err != nil {
+			t.Errorf("Error tweaking %s: %s", v, err)
+			continue
+		}
+
+		if hex.EncodeToString(outputKey) != outputKeys[i] {
+			t.Errorf("Incorrect output key. Expected %s, got %x", outputKeys[i], outputKey)
+		}
+	}
+
+	internalKey, _ := hex.DecodeString(internalKeys[0])
+	if _, _, err := TweakPublicKey(internalKey, make([]byte, 31)); err != ErrInvalidMerkleRoot {
+		t.Errorf("Expected ErrInvalidMerkleRoot, got %v", err)
+	}
+}
+
+func TestOutputKey(t *testing.T) {
+	// BIP86 first receiving address of the "abandon ... about" mnemonic
+	internalKey, _ := hex.DecodeString("cc8a4bc64d897bddc5fbc2f670f7a8ba0b386779106cf1223c6fc5d7cd6fc115")
+	expected := "a60869f0dbcf1dc659c9cecbaf8050135ea9e8cdc487053f1dc6880949dc684c"
+
+	outputKey, err := OutputKey(internalKey)
+	if err != nil {
+		t.Fatalf("Error computing output key: %s", err)
+	}
+
+	if hex.EncodeToString(outputKey) != expected {
+		t.Errorf("Incorrect output key. Expected %s, got %x", expected, outputKey)
+	}
+}
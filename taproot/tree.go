@@ -0,0 +1,185 @@
+package taproot
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+
+	"github.com/coinhako/addrconv/secp256k1"
+)
+
+// LeafVersionTapscript is the leaf version for BIP342 tapscript
+const LeafVersionTapscript = 0xc0
+
+// Control blocks can't prove a leaf deeper than this
+const maxTreeDepth = 128
+
+// Errors returned when building a script tree
+var (
+	ErrInvalidLeafVersion = errors.New("taproot: invalid leaf version")
+	ErrInvalidTree        = errors.New("taproot: invalid script tree")
+	ErrTreeTooDeep        = errors.New("taproot: script tree deeper than 128 levels")
+)
+
+// Leaf is a script in a taproot script tree
+type Leaf struct {
+	Version byte
+	Script  []byte
+}
+
+// Hash returns the TapLeaf hash of the leaf
+func (leaf Leaf) Hash() []byte {
+	return TaggedHash("TapLeaf", []byte{leaf.Version}, compactSize(len(leaf.Script)), leaf.Script)
+}
+
+// Node is either a leaf or a branch with two children in a script tree
+type Node struct {
+	Leaf        *Leaf
+	Left, Right *Node
+}
+
+// NewLeaf returns a tree node holding a single script
+func NewLeaf(version byte, script []byte) *Node {
+	return &Node{Leaf: &Leaf{Version: version, Script: script}}
+}
+
+// NewBranch returns a tree node joining two subtrees
+func NewBranch(left, right *Node) *Node {
+	return &Node{Left: left, Right: right}
+}
+
+// Hash returns the TapLeaf hash for a leaf, or the TapBranch hash of
+// the children, sorted lexicographically, for a branch. Trees deeper
+// than 128 levels return ErrTreeTooDeep.
+func (node *Node) Hash() ([]byte, error) {
+	hash, _, err := node.walk(0)
+	return hash, err
+}
+
+// leafPath is a leaf with the sibling hashes on its path to the root,
+// from the leaf upwards
+type leafPath struct {
+	leaf Leaf
+	path [][]byte
+}
+
+// walk hashes the subtree at depth and collects its leaves in
+// depth-first order, with their paths up to this node. The depth is
+// checked on the way down, so a tree that's too deep is rejected without
+// walking all of it.
+func (node *Node) walk(depth int) ([]byte, []leafPath, error) {
+	if node == nil {
+		return nil, nil, ErrInvalidTree
+	}
+	if depth > maxTreeDepth {
+		return nil, nil, ErrTreeTooDeep
+	}
+
+	if node.Leaf != nil {
+		if node.Left != nil || node.Right != nil {
+			return nil, nil, ErrInvalidTree
+		}
+		// Odd versions would clash with the parity bit in the control
+		// block, and 0x50 is reserved for the annex
+		if node.Leaf.Version&1 != 0 || node.Leaf.Version == 0x50 {
+			return nil, nil, ErrInvalidLeafVersion
+		}
+		return node.Leaf.Hash(), []leafPath{{leaf: *node.Leaf}}, nil
+	}
+
+	left, leftLeaves, err := node.Left.walk(depth + 1)
+	if err != nil {
+		return nil, nil, err
+	}
+	right, rightLeaves, err := node.Right.walk(depth + 1)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i := range leftLeaves {
+		leftLeaves[i].path = append(leftLeaves[i].path, right)
+	}
+	for i := range rightLeaves {
+		rightLeaves[i].path = append(rightLeaves[i].path, left)
+	}
+
+	return branchHash(left, right), append(leftLeaves, rightLeaves...), nil
+}
+
+func branchHash(a, b []byte) []byte {
+	if bytes.Compare(a, b) > 0 {
+		a, b = b, a
+	}
+	return TaggedHash("TapBranch", a, b)
+}
+
+// LeafSpend is a leaf of a script tree along with the control block
+// that proves its inclusion when spending through the script path
+type LeafSpend struct {
+	Leaf         Leaf
+	ControlBlock []byte
+}
+
+// Output is a taproot output committing to an internal key and an
+// optional script tree
+type Output struct {
+	OutputKey  []byte // x-only
+	OddY       bool
+	MerkleRoot []byte // nil for key-path only outputs
+	Leaves     []LeafSpend
+}
+
+// NewOutput tweaks the internal key with the merkle root of the tree,
+// and builds the control block for each leaf, in depth-first order.
+// A nil tree gives a key-path only output. Trees with leaves more than
+// 128 levels deep can't be spent through those leaves, and are rejected.
+func NewOutput(internalKey []byte, tree *Node) (*Output, error) {
+	var output Output
+	var leaves []leafPath
+	if tree != nil {
+		var err error
+		output.MerkleRoot, leaves, err = tree.walk(0)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var err error
+	output.OutputKey, output.OddY, err = TweakPublicKey(internalKey, output.MerkleRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	// The tweak already validated the key
+	key, _ := secp256k1.ParsePublicKey(internalKey)
+	xOnly := key.XOnly()
+
+	parity := byte(0)
+	if output.OddY {
+		parity = 1
+	}
+	for _, leaf := range leaves {
+		controlBlock := []byte{leaf.leaf.Version | parity}
+		controlBlock = append(controlBlock, xOnly...)
+		for _, hash := range leaf.path {
+			controlBlock = append(controlBlock, hash...)
+		}
+		output.Leaves = append(output.Leaves, LeafSpend{Leaf: leaf.leaf, ControlBlock: controlBlock})
+	}
+
+	return &output, nil
+}
+
+func compactSize(n int) []byte {
+	switch {
+	case n < 0xfd:
+		return []byte{byte(n)}
+	case n <= 0xffff:
+		buf := []byte{0xfd, 0, 0}
+		binary.LittleEndian.PutUint16(buf[1:], uint16(n))
+		return buf
+	}
+	buf := []byte{0xfe, 0, 0, 0, 0}
+	binary.LittleEndian.PutUint32(buf[1:], uint32(n))
+	return buf
+}
@@ -0,0 +1,135 @@
+package taproot
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestNewOutput(t *testing.T) {
+	// BIP341 wallet test vectors
+	var internalKeys = []string{
+		"187791b6f712a8ea41c8ecdd0ee77fab3e85263b37e1ec18a3651926b3a6cf27",
+		"f9f400803e683727b14f463836e1e78e1c64417638aa066919291a225f0e8dd8",
+		"e0dfe2300b0dd746a3f8674dfd4525623639042569d829c7f0eed9602d263e6f",
+	}
+	var trees = []*Node{
+		NewLeaf(LeafVersionTapscript, mustDecodeHex("20d85a959b0290bf19bb89ed43c916be835475d013da4b362117393e25a48229b8ac")),
+		NewBranch(
+			NewLeaf(LeafVersionTapscript, mustDecodeHex("2044b178d64c32c4a05cc4f4d1407268f764c940d20ce97abfd44db5c3592b72fdac")),
+			NewLeaf(LeafVersionTapscript, mustDecodeHex("07546170726f6f74")),
+		),
+		NewBranch(
+			NewLeaf(LeafVersionTapscript, mustDecodeHex("2072ea6adcf1d371dea8fba1035a09f3d24ed5a059799bae114084130ee5898e69ac")),
+			NewBranch(
+				NewLeaf(LeafVersionTapscript, mustDecodeHex("202352d137f2f3ab38d1eaa976758873377fa5ebb817372c71e2c542313d4abda8ac")),
+				NewLeaf(LeafVersionTapscript, mustDecodeHex("207337c0dd4253cb86f2c43a2351aadd82cccb12a172cd120452b9bb8324f2186aac")),
+			),
+		),
+	}
+	var merkleRoots = []string{
+		"5b75adecf53548f3ec6ad7d78383bf84cc57b55a3127c72b9a2481752dd88b21",
+		"ab179431c28d3b68fb798957faf5497d69c883c6fb1e1cd9f81483d87bac90cc",
+		"ccbd66c6f7e8fdab47b3a486f59d28262be857f30d4773f2d5ea47f7761ce0e2",
+	}
+	var outputKeys = []string{
+		"147c9c57132f6e7ecddba9800bb0c4449251c92a1e60371ee77557b6620f3ea3",
+		"77e30a5522dd9f894c3f8b8bd4c4b2cf82ca7da8a3ea6a239655c39c050ab220",
+		"91b64d5324723a985170e4dc5a0f84c041804f2cd12660fa5dec09fc21783605",
+	}
+	var controlBlocks = [][]string{
+		{"c1187791b6f712a8ea41c8ecdd0ee77fab3e85263b37e1ec18a3651926b3a6cf27"},
+		nil,
+		{
+			"c0e0dfe2300b0dd746a3f8674dfd4525623639042569d829c7f0eed9602d263e6fffe578e9ea769027e4f5a3de40732f75a88a6353a09d767ddeb66accef85e553",
+			"c0e0dfe2300b0dd746a3f8674dfd4525623639042569d829c7f0eed9602d263e6f9e31407bffa15fefbf5090b149d53959ecdf3f62b1246780238c24501d5ceaf62645a02e0aac1fe69d69755733a9b7621b694bb5b5cde2bbfc94066ed62b9817",
+			"c0e0dfe2300b0dd746a3f8674dfd4525623639042569d829c7f0eed9602d263e6fba982a91d4fc552163cb1c0da03676102d5b7a014304c01f0c77b2b8e888de1c2645a02e0aac1fe69d69755733a9b7621b694bb5b5cde2bbfc94066ed62b9817",
+		},
+	}
+	var leafCounts = []int{1, 2, 3}
+
+	for i, v := range internalKeys {
+		output, err := NewOutput(mustDecodeHex(v), trees[i])
+		if err != nil {
+			t.Errorf("Error building output for %s: %s", v, err)
+			continue
+		}
+
+		if hex.EncodeToString(output.MerkleRoot) != merkleRoots[i] {
+			t.Errorf("Incorrect merkle root. Expected %s, got %x", merkleRoots[i], output.MerkleRoot)
+		}
+
+		if hex.EncodeToString(output.OutputKey) != outputKeys[i] {
+			t.Errorf("Incorrect output key. Expected %s, got %x", outputKeys[i], output.OutputKey)
+		}
+
+		if len(output.Leaves) != leafCounts[i] {
+			t.Errorf("Incorrect number of leaves. Expected %d, got %d", leafCounts[i], len(output.Leaves))
+			continue
+		}
+
+		for j, leaf := range output.Leaves {
+			if controlBlocks[i] != nil && hex.EncodeToString(leaf.ControlBlock) != controlBlocks[i][j] {
+				t.Errorf("Incorrect control block. Expected %s, got %x", controlBlocks[i][j], leaf.ControlBlock)
+			}
+
+			// Walking the path from the leaf must lead back to the root
+			hash := leaf.Leaf.Hash()
+			for path := leaf.ControlBlock[33:]; len(path) > 0; path = path[32:] {
+				hash = branchHash(hash, path[:32])
+			}
+			if !bytes.Equal(hash, output.MerkleRoot) {
+				t.Errorf("Control block %x doesn't lead to the merkle root", leaf.ControlBlock)
+			}
+		}
+	}
+
+	var invalidTrees = []*Node{
+		NewLeaf(0xc1, nil),
+		NewLeaf(0x50, nil),
+		NewBranch(NewLeaf(LeafVersionTapscript, nil), nil),
+	}
+	for _, tree := range invalidTrees {
+		if _, err := NewOutput(mustDecodeHex(internalKeys[0]), tree); err == nil {
+			t.Errorf("Expected error for invalid tree")
+		}
+	}
+}
+
+func TestTreeDepth(t *testing.T) {
+	internalKey := mustDecodeHex("187791b6f712a8ea41c8ecdd0ee77fab3e85263b37e1ec18a3651926b3a6cf27")
+
+	// A chain of branches puts the deepest leaves at depth levels
+	chain := func(levels int) *Node {
+		tree := NewLeaf(LeafVersionTapscript, []byte{0x51})
+		for i := 0; i < levels; i++ {
+			tree = NewBranch(tree, NewLeaf(LeafVersionTapscript, []byte{byte(i)}))
+		}
+		return tree
+	}
+
+	if _, err := NewOutput(internalKey, chain(128)); err != nil {
+		t.Errorf("Error building a tree 128 levels deep: %s", err)
+	}
+
+	if _, err := NewOutput(internalKey, chain(129)); err != ErrTreeTooDeep {
+		t.Errorf("Expected ErrTreeTooDeep, got %v", err)
+	}
+
+	// Deep trees are rejected without walking the whole tree
+	deep := chain(1000000)
+	if _, err := NewOutput(internalKey, deep); err != ErrTreeTooDeep {
+		t.Errorf("Expected ErrTreeTooDeep, got %v", err)
+	}
+	if _, err := deep.Hash(); err != ErrTreeTooDeep {
+		t.Errorf("Expected ErrTreeTooDeep hashing, got %v", err)
+	}
+}
+
+func mustDecodeHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}